@@ -0,0 +1,213 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zhizh/go-getoptions/option"
+)
+
+// Positional arguments are defined in order and are bound after all options have been parsed.
+// The remaining []string returned by Parse still contains the positional arguments.
+// Unknown options passed through with opt.SetUnknownMode are returned in remaining but not bound to arguments.
+//
+// Arguments are optional by default, use opt.Required to make them required.
+// The following ModifyFns apply to arguments: opt.Required, opt.Description, opt.ValidValues, opt.Validate, opt.CompleteFiles, opt.CompleteDirs, opt.CompleteIgnoreCase and opt.CompletionFn.
+//
+// When arguments are defined, passing more arguments than the ones defined results in an error.
+// Arguments are shown in the help synopsis, unless opt.HelpSynopsisArgs is set, and in the ARGUMENTS help section.
+// Completion uses the completion of the argument at the position being completed and stops after the last argument, unless it is variadic.
+
+// StringArg - define a `string` positional argument.
+func (gopt *GetOpt) StringArg(name string, fns ...ModifyFn) *string {
+	var s string
+	gopt.StringArgVar(&s, name, fns...)
+	return &s
+}
+
+// StringArgVar - define a `string` positional argument.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) StringArgVar(p *string, name string, fns ...ModifyFn) {
+	gopt.setArgument(option.New(name, option.StringType, p), fns...)
+}
+
+// IntArg - define an `int` positional argument.
+func (gopt *GetOpt) IntArg(name string, fns ...ModifyFn) *int {
+	var i int
+	gopt.IntArgVar(&i, name, fns...)
+	return &i
+}
+
+// IntArgVar - define an `int` positional argument.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) IntArgVar(p *int, name string, fns ...ModifyFn) {
+	opt := option.New(name, option.IntType, p)
+	opt.DefaultStr = fmt.Sprintf("%d", *p)
+	gopt.setArgument(opt, fns...)
+}
+
+// Float64Arg - define a `float64` positional argument.
+func (gopt *GetOpt) Float64Arg(name string, fns ...ModifyFn) *float64 {
+	var f float64
+	gopt.Float64ArgVar(&f, name, fns...)
+	return &f
+}
+
+// Float64ArgVar - define a `float64` positional argument.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) Float64ArgVar(p *float64, name string, fns ...ModifyFn) {
+	opt := option.New(name, option.Float64Type, p)
+	opt.DefaultStr = fmt.Sprintf("%f", *p)
+	gopt.setArgument(opt, fns...)
+}
+
+// StringSliceArg - define a variadic `[]string` positional argument.
+// It takes all the remaining arguments so it must be the last argument defined.
+func (gopt *GetOpt) StringSliceArg(name string, fns ...ModifyFn) *[]string {
+	s := []string{}
+	gopt.StringSliceArgVar(&s, name, fns...)
+	return &s
+}
+
+// StringSliceArgVar - define a variadic `[]string` positional argument.
+// It takes all the remaining arguments so it must be the last argument defined.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) StringSliceArgVar(p *[]string, name string, fns ...ModifyFn) {
+	opt := option.New(name, option.StringRepeatType, p)
+	opt.DefaultStr = "[]"
+	gopt.setArgument(opt, fns...)
+}
+
+// IntSliceArg - define a variadic `[]int` positional argument.
+// It takes all the remaining arguments so it must be the last argument defined.
+func (gopt *GetOpt) IntSliceArg(name string, fns ...ModifyFn) *[]int {
+	s := []int{}
+	gopt.IntSliceArgVar(&s, name, fns...)
+	return &s
+}
+
+// IntSliceArgVar - define a variadic `[]int` positional argument.
+// It takes all the remaining arguments so it must be the last argument defined.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) IntSliceArgVar(p *[]int, name string, fns ...ModifyFn) {
+	opt := option.New(name, option.IntRepeatType, p)
+	opt.DefaultStr = "[]"
+	gopt.setArgument(opt, fns...)
+}
+
+// CompleteFiles - Complete the option argument or positional argument with file names.
+// When glob patterns are given, as in filepath.Match, only the files matching one of them are completed.
+// Directories are always completed to allow navigating into them.
+//
+//     opt.String("config", "", opt.CompleteFiles("*.yaml", "*.yml"))
+func (gopt *GetOpt) CompleteFiles(patterns ...string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetFileCompletion(true)
+		opt.SetFilePatterns(patterns...)
+	}
+}
+
+// CompleteDirs - Complete the option argument or positional argument with directory names.
+func (gopt *GetOpt) CompleteDirs() ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetDirCompletion(true)
+	}
+}
+
+// CompleteIgnoreCase - Match the prefix ignoring case when completing file or directory names with opt.CompleteFiles or opt.CompleteDirs.
+//
+//     opt.String("config", "", opt.CompleteFiles("*.yaml"), opt.CompleteIgnoreCase())
+func (gopt *GetOpt) CompleteIgnoreCase() ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetIgnoreCase(true)
+	}
+}
+
+// CompletionFn - Complete the option argument or positional argument with the results of fn at completion time.
+// The results are filtered by the prefix being completed and discarded if fn doesn't return within `completion.CallbackTimeout`.
+//
+//     opt.String("branch", "", opt.CompletionFn(func(ctx context.Context, prefix string) []string {
+//         return gitBranches(ctx)
+//     }))
+func (gopt *GetOpt) CompletionFn(fn func(ctx context.Context, prefix string) []string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetCompletionFn(fn)
+	}
+}
+
+// isVariadic - Indicates if the argument takes all the remaining arguments.
+func isVariadic(opt *option.Option) bool {
+	return opt.OptType == option.StringRepeatType || opt.OptType == option.IntRepeatType
+}
+
+// setArgument will *panic* if the argument is defined twice, if it is defined after a variadic argument
+// or if it is a required argument defined after an optional one.
+// This is not an error because the programmer has to fix this!
+func (gopt *GetOpt) setArgument(opt *option.Option, fns ...ModifyFn) {
+	for _, fn := range fns {
+		fn(opt)
+	}
+	for _, arg := range gopt.arguments {
+		if arg.Name == opt.Name {
+			panic(fmt.Sprintf("Argument '%s' is already defined", opt.Name))
+		}
+		if isVariadic(arg) {
+			panic(fmt.Sprintf("Argument '%s' is defined after variadic argument '%s'", opt.Name, arg.Name))
+		}
+		if opt.IsRequired && !arg.IsRequired {
+			panic(fmt.Sprintf("Required argument '%s' is defined after optional argument '%s'", opt.Name, arg.Name))
+		}
+	}
+	gopt.arguments = append(gopt.arguments, opt)
+
+	// Completion, the node only completes the operand at the argument position
+	if node := valueCompletionNode(opt); node != nil {
+		node.Argument = len(gopt.arguments)
+		node.Variadic = isVariadic(opt)
+		gopt.completion.AddChild(node)
+	}
+}
+
+// bindArguments - Saves the operands into the positional argument definitions.
+func (gopt *GetOpt) bindArguments(remaining []string) error {
+	if len(gopt.arguments) == 0 {
+		return nil
+	}
+	i := 0
+	for _, arg := range gopt.arguments {
+		if i >= len(remaining) {
+			if arg.IsRequired {
+				return &MissingRequiredError{Name: arg.Name, Msg: arg.IsRequiredErr, IsArgument: true}
+			}
+			continue
+		}
+		arg.SetCalled(arg.Name)
+		if isVariadic(arg) {
+			for _, e := range remaining[i:] {
+				err := arg.Save(e)
+				if err != nil {
+					return err
+				}
+			}
+			i = len(remaining)
+			continue
+		}
+		err := arg.Save(remaining[i])
+		if err != nil {
+			return err
+		}
+		i++
+	}
+	if i < len(remaining) {
+		return &TooManyArgumentsError{Arguments: remaining[i:]}
+	}
+	return nil
+}
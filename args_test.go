@@ -0,0 +1,383 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zhizh/go-getoptions/completion"
+	"github.com/zhizh/go-getoptions/text"
+)
+
+func TestArguments(t *testing.T) {
+	type result struct {
+		src    string
+		count  int
+		ratio  float64
+		files  []string
+		remain []string
+	}
+	setup := func() (*GetOpt, *result) {
+		r := &result{}
+		opt := New()
+		opt.Bool("debug", false)
+		opt.StringArgVar(&r.src, "src", opt.Required(), opt.Description("source"))
+		opt.IntArgVar(&r.count, "count")
+		opt.Float64ArgVar(&r.ratio, "ratio")
+		opt.StringSliceArgVar(&r.files, "files")
+		return opt, r
+	}
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected result
+		err      error
+	}{
+		{"required only", []string{"a"}, result{src: "a", remain: []string{"a"}}, nil},
+		{"mixed with options", []string{"a", "--debug", "2"}, result{src: "a", count: 2, remain: []string{"a", "2"}}, nil},
+		{"variadic", []string{"a", "2", "0.5", "x", "y"},
+			result{src: "a", count: 2, ratio: 0.5, files: []string{"x", "y"}, remain: []string{"a", "2", "0.5", "x", "y"}}, nil},
+		{"double dash", []string{"--", "-a", "2"}, result{src: "-a", count: 2, remain: []string{"-a", "2"}}, nil},
+		{"missing required", []string{}, result{}, fmt.Errorf(text.ErrorMissingRequiredArgument, "src")},
+		{"conversion error", []string{"a", "x"}, result{src: "a"}, fmt.Errorf(text.ErrorConvertToInt, "count", "x")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, r := setup()
+			remaining, err := opt.Parse(tt.input)
+			if (err == nil) != (tt.err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Errorf("Unexpected error: got '%v', expected '%v'", err, tt.err)
+			}
+			r.remain = remaining
+			if !reflect.DeepEqual(*r, tt.expected) {
+				t.Errorf("Unexpected result: got %+v, expected %+v", *r, tt.expected)
+			}
+		})
+	}
+
+	t.Run("too many arguments", func(t *testing.T) {
+		opt := New()
+		opt.StringArg("src")
+		_, err := opt.Parse([]string{"a", "b", "c"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorTooManyArguments, []string{"b", "c"}) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("require order", func(t *testing.T) {
+		var file string
+		opt := New()
+		opt.SetRequireOrder()
+		opt.Bool("v", false)
+		opt.StringArgVar(&file, "file", opt.Required())
+		remaining, err := opt.Parse([]string{"-v", "a.txt"})
+		if err != nil || file != "a.txt" || !reflect.DeepEqual(remaining, []string{"a.txt"}) {
+			t.Errorf("Unexpected result: %v, %v, %v", file, remaining, err)
+		}
+		_, err = opt.Parse([]string{"-v", "a.txt", "extra"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorTooManyArguments, []string{"extra"}) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		_, err = opt.Parse([]string{"-v"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingRequiredArgument, "file") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("pass unknown options", func(t *testing.T) {
+		var file string
+		opt := New()
+		opt.SetUnknownMode(Pass)
+		opt.StringArgVar(&file, "file")
+		remaining, err := opt.Parse([]string{"--unknown", "a.txt"})
+		if err != nil || file != "a.txt" || !reflect.DeepEqual(remaining, []string{"--unknown", "a.txt"}) {
+			t.Errorf("Unexpected result: %v, %v, %v", file, remaining, err)
+		}
+
+		file = ""
+		opt.SetRequireOrder()
+		remaining, err = opt.Parse([]string{"--unknown", "a.txt"})
+		if err != nil || file != "a.txt" || !reflect.DeepEqual(remaining, []string{"--unknown", "a.txt"}) {
+			t.Errorf("Unexpected result: %v, %v, %v", file, remaining, err)
+		}
+	})
+
+	t.Run("no arguments defined", func(t *testing.T) {
+		opt := New()
+		remaining, err := opt.Parse([]string{"a", "b"})
+		if err != nil || !reflect.DeepEqual(remaining, []string{"a", "b"}) {
+			t.Errorf("Unexpected result: %v, %v", remaining, err)
+		}
+	})
+
+	t.Run("modify fns", func(t *testing.T) {
+		opt := New()
+		src := opt.StringArg("src", opt.Required("Missing source file!"))
+		format := opt.StringArg("format", opt.ValidValues("json", "yaml"))
+		ids := opt.IntSliceArg("ids")
+		_, err := opt.Parse([]string{})
+		if err == nil || err.Error() != "Missing source file!" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		_, err = opt.Parse([]string{"a", "xml"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorInvalidValue, "format", "xml", []string{"json", "yaml"}) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		opt = New()
+		src = opt.StringArg("src", opt.Required("Missing source file!"))
+		format = opt.StringArg("format", opt.ValidValues("json", "yaml"))
+		ids = opt.IntSliceArg("ids")
+		_, err = opt.Parse([]string{"a", "yaml", "1", "2"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *src != "a" || *format != "yaml" || !reflect.DeepEqual(*ids, []int{1, 2}) {
+			t.Errorf("Unexpected values: %v, %v, %v", *src, *format, *ids)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		for name, fn := range map[string]func(opt *GetOpt){
+			"duplicate":         func(opt *GetOpt) { opt.StringArg("a"); opt.StringArg("a") },
+			"after variadic":    func(opt *GetOpt) { opt.StringSliceArg("a"); opt.StringArg("b") },
+			"required optional": func(opt *GetOpt) { opt.StringArg("a"); opt.StringArg("b", opt.Required()) },
+		} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("%s didn't panic", name)
+					}
+				}()
+				fn(New())
+			}()
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt, _ := setup()
+		expected := `SYNOPSIS:
+    go-getoptions.test [--debug] <src> [<count>] [<ratio>] [<files>...]
+
+ARGUMENTS:
+    <src>           source
+    [<count>]       (default: 0)
+    [<ratio>]       (default: 0.000000)
+    [<files>...]
+
+OPTIONS:
+    --debug    (default: false)
+
+`
+		if opt.Help() != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(opt.Help(), expected))
+		}
+		opt.HelpSynopsisArgs("<src> [<count> [<ratio> [<files>...]]]")
+		expected = `SYNOPSIS:
+    go-getoptions.test [--debug] <src> [<count> [<ratio> [<files>...]]]
+
+`
+		if opt.Help(HelpSynopsis) != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(opt.Help(HelpSynopsis), expected))
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		called := false
+		exitFn = func(code int) { called = true }
+		defer func() { exitFn = os.Exit }()
+		defer os.Setenv("COMP_LINE", "")
+		defer func() { completionWriter = os.Stdout }()
+		for _, tt := range []struct {
+			name     string
+			compLine string
+			expected string
+		}{
+			{"files", "test args", "args.go\nargs_test.go\n"},
+			{"valid values", "test args.go y", "yaml\n"},
+			{"command", "test cmd j", "json\n"},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				called = false
+				buf := new(bytes.Buffer)
+				completionWriter = buf
+				os.Setenv("COMP_LINE", tt.compLine)
+				opt := New()
+				opt.StringArg("file", opt.CompleteFiles())
+				opt.StringArg("format", opt.ValidValues("json", "yaml"))
+				cmd := opt.NewCommand("cmd", "")
+				cmd.StringArg("format", opt.ValidValues("json", "yaml"))
+				_, err := opt.Parse([]string{})
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				if !called {
+					t.Errorf("COMP_LINE set and exit wasn't called")
+				}
+				if buf.String() != tt.expected {
+					t.Errorf("Error\ngot: '%s', expected: '%s'\n", buf.String(), tt.expected)
+				}
+			})
+		}
+	})
+}
+
+func TestArgumentCompletionPosition(t *testing.T) {
+	opt := New()
+	opt.StringArg("shell", opt.ValidValues("bash", "zsh"))
+	opt.StringSliceArg("dir", opt.CompleteDirs())
+	for _, tt := range []struct {
+		line     string
+		expected []string
+	}{
+		{"test ", []string{"bash", "zsh"}},
+		{"test bash h", []string{"help/ ", "help/"}},
+		{"test bash help/ c", []string{"compat/", "completion/", "completiontest/"}},
+		{"test bash help/ z", []string{}},
+	} {
+		got := opt.Complete(tt.line, -1)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%q: got %v, expected %v", tt.line, got, tt.expected)
+		}
+	}
+
+	// No completions after the last argument
+	opt = New()
+	opt.StringArg("file", opt.CompleteFiles())
+	got := opt.Complete("test args.go a", -1)
+	if !reflect.DeepEqual(got, []string{}) {
+		t.Errorf("got %v, expected []", got)
+	}
+}
+
+func TestCompletionFn(t *testing.T) {
+	called := false
+	exitFn = func(code int) { called = true }
+	defer func() { exitFn = os.Exit }()
+	defer os.Setenv("COMP_LINE", "")
+	defer func() { completionWriter = os.Stdout }()
+
+	branches := func(ctx context.Context, prefix string) []string {
+		return []string{"main", "feature-b", "feature-a"}
+	}
+	for _, tt := range []struct {
+		name     string
+		compLine string
+		expected string
+	}{
+		{"option equal", "test --branch=f", "feature-a\nfeature-b\n"},
+		{"option separate", "test --branch ", "feature-a\nfeature-b\nmain\n"},
+		{"alias", "test -b m", "main\n"},
+		{"argument", "test ma", "main\n"},
+		{"command", "test checkout feature-", "feature--passed\nfeature-a\nfeature-b\n"},
+		{"command prefix", "test checkout ab", "ab-passed\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			buf := new(bytes.Buffer)
+			completionWriter = buf
+			os.Setenv("COMP_LINE", tt.compLine)
+			opt := New()
+			opt.String("branch", "", opt.Alias("b"), opt.CompletionFn(branches))
+			opt.StringArg("ref", opt.CompletionFn(branches))
+			opt.NewCommand("checkout", "").CustomCompletionFn(func(ctx context.Context, prefix string) []string {
+				return []string{"feature-a", "feature-b", prefix + "-passed"}
+			})
+			_, err := opt.Parse([]string{})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !called {
+				t.Errorf("COMP_LINE set and exit wasn't called")
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Error\ngot: '%s', expected: '%s'\n", buf.String(), tt.expected)
+			}
+		})
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		defer func(d time.Duration) { completion.CallbackTimeout = d }(completion.CallbackTimeout)
+		completion.CallbackTimeout = 10 * time.Millisecond
+		buf := new(bytes.Buffer)
+		completionWriter = buf
+		os.Setenv("COMP_LINE", "test --branch=")
+		opt := New()
+		opt.String("branch", "", opt.CompletionFn(func(ctx context.Context, prefix string) []string {
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			return []string{"main"}
+		}))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if buf.String() != "\n" {
+			t.Errorf("Error\ngot: '%s', expected: '%s'\n", buf.String(), "\n")
+		}
+	})
+}
+
+func TestOptionValueCompletion(t *testing.T) {
+	called := false
+	exitFn = func(code int) { called = true }
+	defer func() { exitFn = os.Exit }()
+	defer os.Setenv("COMP_LINE", "")
+	defer func() { completionWriter = os.Stdout }()
+
+	for _, tt := range []struct {
+		name     string
+		compLine string
+		expected string
+	}{
+		{"files equal", "test --config=args", "args.go\nargs_test.go\n"},
+		{"files separate", "test --config args_", "args_test.go\n"},
+		{"files alias", "test -c=completion/test/test_tree/a", "completion/test/test_tree/aFile1\ncompletion/test/test_tree/aFile2\n"},
+		{"single dir", "test --config completion/test/test_tree/bDir1", "completion/test/test_tree/bDir1/\ncompletion/test/test_tree/bDir1/ \n"},
+		{"command", "test cmd --config=args_", "args_test.go\n"},
+		{"valid values over files", "test --format=", "json\nyaml\n"},
+		{"after value", "test --config args.go --f", "--format\n"},
+		{"dirs", "test --dir=completion/test/test_tree/", "completion/test/test_tree/bDir1/\ncompletion/test/test_tree/bDir2/\n"},
+		{"patterns", "test --source args", "args_test.go\n"},
+		{"patterns dirs", "test --source completion/test/test_tree/", "completion/test/test_tree/bDir1/\ncompletion/test/test_tree/bDir2/\n"},
+		{"argument dirs", "test completion/test/test_tree/b", "completion/test/test_tree/bDir1/\ncompletion/test/test_tree/bDir2/\n"},
+		{"ignore case", "test --input completion/test/test_tree/CF", "completion/test/test_tree/cFile1\ncompletion/test/test_tree/cFile2\n"},
+		{"case sensitive", "test --config completion/test/test_tree/CF", "\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			buf := new(bytes.Buffer)
+			completionWriter = buf
+			os.Setenv("COMP_LINE", tt.compLine)
+			opt := New()
+			opt.String("config", "", opt.Alias("c"), opt.CompleteFiles())
+			opt.String("format", "", opt.ValidValues("json", "yaml"), opt.CompleteFiles())
+			opt.String("dir", "", opt.CompleteDirs())
+			opt.String("source", "", opt.CompleteFiles("*_test.go"))
+			opt.String("input", "", opt.CompleteFiles(), opt.CompleteIgnoreCase())
+			opt.StringArg("out", opt.CompleteDirs())
+			opt.NewCommand("cmd", "")
+			_, err := opt.Parse([]string{})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !called {
+				t.Errorf("COMP_LINE set and exit wasn't called")
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Error\ngot: '%s', expected: '%s'\n", buf.String(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// BindStruct - Defines options from the fields of the given struct pointer.
+// Only fields with a `getoptions` struct tag are bound, use `getoptions:"-"` to skip a field explicitly.
+//
+// The tag is a comma separated list of entries:
+//
+//     name=<name>        Option name, defaults to the field name in kebab-case: DryRun -> dry-run.
+//     alias=<a>|<b>      Option aliases.
+//     env=<VAR>          Same as opt.GetEnv("VAR").
+//     config=<key>       Same as opt.ConfigKey("key").
+//     required           Same as opt.Required().
+//     argname=<name>     Same as opt.ArgName("name").
+//     valid=<a>|<b>      Same as opt.ValidValues("a", "b").
+//     min=<n>,max=<n>    Min and max arguments for slice and map fields, defaults to 1.
+//     desc=<text>        Same as opt.Description("text"). Must be the last entry, it can contain commas.
+//
+// For example:
+//
+//     type Config struct {
+//         Host    string        `getoptions:"alias=H,env=HOST,required,desc=Host to connect to"`
+//         Port    int           `getoptions:"alias=p,desc=Port to connect to"`
+//         Timeout time.Duration `getoptions:"desc=Connection timeout"`
+//         Log     LogCommand    `getoptions:"desc=Show logs"`
+//     }
+//
+// The current field value is used as the option default.
+//
+// Supported field types: bool, string, int, int64, uint, uint64, float64, time.Duration,
+// their slices (except bool and float64), map[string]string and fields whose pointer implements the Value interface.
+//
+// Fields of struct type define a command built with opt.NewCommand, named after the field and bound recursively.
+// When the struct pointer implements `Run(context.Context, *GetOpt, []string) error` it is set as the command function.
+//
+// BindStruct will *panic* if ptr is not a pointer to a struct, if a tagged field is unexported or of an unsupported type,
+// or if the tag is malformed.
+// This is not an error because the programmer has to fix this!
+func (gopt *GetOpt) BindStruct(ptr interface{}) *GetOpt {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("BindStruct requires a non nil pointer to a struct, got %T", ptr))
+	}
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("getoptions")
+		if !ok || tag == "-" {
+			continue
+		}
+		if field.PkgPath != "" {
+			panic(fmt.Sprintf("BindStruct field '%s' must be exported", field.Name))
+		}
+		st := parseStructTag(field.Name, tag)
+		gopt.bindField(v.Field(i).Addr().Interface(), st)
+	}
+	if r, ok := ptr.(commandRunner); ok {
+		gopt.SetCommandFn(r.Run)
+	}
+	return gopt
+}
+
+// commandRunner - Implemented by bound structs that define the command function.
+type commandRunner interface {
+	Run(context.Context, *GetOpt, []string) error
+}
+
+type structTag struct {
+	field    string
+	name     string
+	aliases  []string
+	env      string
+	config   string
+	required bool
+	argName  string
+	valid    []string
+	min      int
+	max      int
+	desc     string
+}
+
+func parseStructTag(fieldName, tag string) structTag {
+	st := structTag{field: fieldName, name: kebabCase(fieldName), min: 1, max: 1}
+	for tag != "" {
+		var entry string
+		if strings.HasPrefix(tag, "desc=") {
+			entry, tag = tag, ""
+		} else {
+			parts := strings.SplitN(tag, ",", 2)
+			entry = parts[0]
+			tag = ""
+			if len(parts) == 2 {
+				tag = parts[1]
+			}
+		}
+		keyValue := strings.SplitN(entry, "=", 2)
+		key := strings.TrimSpace(keyValue[0])
+		value := ""
+		if len(keyValue) == 2 {
+			value = keyValue[1]
+		}
+		switch key {
+		case "name":
+			st.name = value
+		case "alias":
+			st.aliases = append(st.aliases, strings.Split(value, "|")...)
+		case "env":
+			st.env = value
+		case "config":
+			st.config = value
+		case "required":
+			st.required = true
+		case "argname":
+			st.argName = value
+		case "valid":
+			st.valid = strings.Split(value, "|")
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				panic(fmt.Sprintf("BindStruct field '%s' %s must be an integer, got '%s'", fieldName, key, value))
+			}
+			if key == "min" {
+				st.min = n
+			} else {
+				st.max = n
+			}
+		case "desc":
+			st.desc = value
+		case "":
+		default:
+			panic(fmt.Sprintf("BindStruct field '%s' has unknown tag entry '%s'", fieldName, key))
+		}
+	}
+	if st.name == "" {
+		panic(fmt.Sprintf("BindStruct field '%s' name must not be empty", fieldName))
+	}
+	return st
+}
+
+// modifyFns - Returns the ModifyFns equivalent to the tag entries.
+func (gopt *GetOpt) modifyFns(st structTag) []ModifyFn {
+	fns := []ModifyFn{}
+	if len(st.aliases) > 0 {
+		fns = append(fns, gopt.Alias(st.aliases...))
+	}
+	if st.required {
+		fns = append(fns, gopt.Required())
+	}
+	if st.desc != "" {
+		fns = append(fns, gopt.Description(st.desc))
+	}
+	if st.argName != "" {
+		fns = append(fns, gopt.ArgName(st.argName))
+	}
+	if len(st.valid) > 0 {
+		fns = append(fns, gopt.ValidValues(st.valid...))
+	}
+	if st.config != "" {
+		fns = append(fns, gopt.ConfigKey(st.config))
+	}
+	if st.env != "" {
+		fns = append(fns, gopt.GetEnv(st.env))
+	}
+	return fns
+}
+
+// bindField - Defines the option or command for the given field pointer.
+func (gopt *GetOpt) bindField(ptr interface{}, st structTag) {
+	fns := gopt.modifyFns(st)
+	switch p := ptr.(type) {
+	case Value:
+		gopt.Var(p, st.name, fns...)
+	case *bool:
+		gopt.BoolVar(p, st.name, *p, fns...)
+	case *string:
+		gopt.StringVar(p, st.name, *p, fns...)
+	case *int:
+		gopt.IntVar(p, st.name, *p, fns...)
+	case *int64:
+		gopt.Int64Var(p, st.name, *p, fns...)
+	case *uint:
+		gopt.UintVar(p, st.name, *p, fns...)
+	case *uint64:
+		gopt.Uint64Var(p, st.name, *p, fns...)
+	case *float64:
+		gopt.Float64Var(p, st.name, *p, fns...)
+	case *time.Duration:
+		gopt.DurationVar(p, st.name, *p, fns...)
+	case *[]string:
+		gopt.StringSliceVar(p, st.name, st.min, st.max, fns...)
+	case *[]int:
+		gopt.IntSliceVar(p, st.name, st.min, st.max, fns...)
+	case *[]int64:
+		gopt.Int64SliceVar(p, st.name, st.min, st.max, fns...)
+	case *[]uint:
+		gopt.UintSliceVar(p, st.name, st.min, st.max, fns...)
+	case *[]uint64:
+		gopt.Uint64SliceVar(p, st.name, st.min, st.max, fns...)
+	case *[]time.Duration:
+		gopt.DurationSliceVar(p, st.name, st.min, st.max, fns...)
+	case *map[string]string:
+		gopt.StringMapVar(p, st.name, st.min, st.max, fns...)
+	default:
+		if reflect.TypeOf(ptr).Elem().Kind() != reflect.Struct {
+			panic(fmt.Sprintf("BindStruct field '%s' has unsupported type %T", st.field, ptr))
+		}
+		cmd := gopt.NewCommand(st.name, st.desc)
+		cmd.BindStruct(ptr)
+	}
+}
+
+// kebabCase - Converts a Go field name into an option name: DryRun -> dry-run, HTTPPort -> http-port, IDs -> ids.
+func kebabCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Split on lower to upper changes and before the last capital of an acronym followed by a word: HTTPServer.
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+2 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]) && unicode.IsLower(runes[i+2]))) {
+				b.WriteRune('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
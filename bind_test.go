@@ -0,0 +1,205 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+package getoptions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zhizh/go-getoptions/text"
+)
+
+type bindLogCommand struct {
+	Since  string   `getoptions:"desc=Show logs since, for example: 1 week"`
+	Filter []string `getoptions:"alias=f,min=1,max=3"`
+	called bool
+}
+
+func (c *bindLogCommand) Run(ctx context.Context, opt *GetOpt, args []string) error {
+	c.called = true
+	return nil
+}
+
+type bindConfig struct {
+	Host     string            `getoptions:"alias=H|hostname,env=_get_opt_bind_host,required,desc=Host to connect to"`
+	Port     int               `getoptions:"alias=p"`
+	DryRun   bool              `getoptions:""`
+	HTTPPort uint              `getoptions:"name=http"`
+	Ratio    float64           `getoptions:""`
+	Size     int64             `getoptions:""`
+	Count    uint64            `getoptions:""`
+	Timeout  time.Duration     `getoptions:"argname=duration"`
+	Format   string            `getoptions:"valid=json|yaml"`
+	IDs      []int             `getoptions:"name=id"`
+	Labels   map[string]string `getoptions:"min=1,max=2"`
+	Level    logLevel          `getoptions:""`
+	Log      bindLogCommand    `getoptions:"desc=Show logs"`
+	Ignored  string
+	Skipped  string `getoptions:"-"`
+}
+
+// readmeLogCommand and readmeConfig mirror the README.adoc struct binding example.
+type readmeLogCommand struct {
+	Since string `getoptions:"desc=Show logs since, for example: 1 week"`
+}
+
+func (c *readmeLogCommand) Run(ctx context.Context, opt *GetOpt, args []string) error {
+	return nil
+}
+
+type readmeConfig struct {
+	Host   string           `getoptions:"alias=H,env=HOST,required,desc=Host to connect to"`
+	Port   int              `getoptions:"alias=p,desc=Port to connect to"`
+	DryRun bool             `getoptions:"desc=Don't make any changes"` // --dry-run
+	Format string           `getoptions:"valid=json|yaml"`
+	Tags   []string         `getoptions:"name=tag,min=1,max=99"`
+	Log    readmeLogCommand `getoptions:"desc=Show logs"` // log command
+}
+
+func TestBindStruct(t *testing.T) {
+	t.Run("readme example", func(t *testing.T) {
+		c := readmeConfig{Port: 80}
+		opt := New()
+		opt.BindStruct(&c)
+		_, err := opt.Parse([]string{"-H", "example.com", "--dry-run", "--tag", "a", "b"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := readmeConfig{Host: "example.com", Port: 80, DryRun: true, Tags: []string{"a", "b"}}
+		if !reflect.DeepEqual(c, expected) {
+			t.Errorf("Unexpected values:\ngot      %+v\nexpected %+v", c, expected)
+		}
+		if opt.commands["log"].CommandFn == nil {
+			t.Errorf("Command function not set")
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		os.Setenv("_get_opt_bind_host", "env.com")
+		defer os.Unsetenv("_get_opt_bind_host")
+		c := bindConfig{Port: 80, Format: "json", Level: "info"}
+		opt := New()
+		opt.BindStruct(&c)
+		_, err := opt.Parse([]string{"-p", "8080", "--dry-run", "--http", "443", "--ratio", "0.5", "--size=-1", "--count", "2",
+			"--timeout", "5s", "--format", "yaml", "--id", "1", "--id", "2", "--labels", "a=b", "c=d", "--level", "debug"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := bindConfig{Host: "env.com", Port: 8080, DryRun: true, HTTPPort: 443, Ratio: 0.5, Size: -1, Count: 2,
+			Timeout: 5 * time.Second, Format: "yaml", IDs: []int{1, 2}, Labels: map[string]string{"a": "b", "c": "d"}, Level: "debug"}
+		c.Log = bindLogCommand{}
+		if !reflect.DeepEqual(c, expected) {
+			t.Errorf("Unexpected values:\ngot      %+v\nexpected %+v", c, expected)
+		}
+		if opt.Value("port") != 8080 || opt.Value("Ignored") != nil || opt.Value("skipped") != nil {
+			t.Errorf("Unexpected option values")
+		}
+	})
+
+	t.Run("defaults and errors", func(t *testing.T) {
+		c := bindConfig{Port: 80}
+		opt := New()
+		opt.BindStruct(&c)
+		_, err := opt.Parse([]string{"--hostname", "example.com"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if c.Host != "example.com" || c.Port != 80 {
+			t.Errorf("Unexpected values: %v, %v", c.Host, c.Port)
+		}
+
+		opt = New()
+		opt.BindStruct(&bindConfig{})
+		_, err = opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingRequiredOption, "host") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		opt = New()
+		opt.BindStruct(&bindConfig{})
+		_, err = opt.Parse([]string{"-H", "x", "--format", "xml"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorInvalidValue, "format", "xml", []string{"json", "yaml"}) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		c := bindConfig{}
+		opt := New()
+		opt.SetRequireOrder()
+		opt.SetUnknownMode(Pass)
+		opt.BindStruct(&c)
+		remaining, err := opt.Parse([]string{"-H", "x", "log", "--since", "1 week", "-f", "a", "b"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", remaining)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !c.Log.called || c.Log.Since != "1 week" || !reflect.DeepEqual(c.Log.Filter, []string{"a", "b"}) {
+			t.Errorf("Unexpected values: %+v", c.Log)
+		}
+		if opt.commands["log"].description != "Show logs" {
+			t.Errorf("Unexpected description: %s", opt.commands["log"].description)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		type unexported struct {
+			host string `getoptions:""`
+		}
+		type unsupported struct {
+			Flags []bool `getoptions:""`
+		}
+		type unknownTag struct {
+			Host string `getoptions:"short=h"`
+		}
+		type badMin struct {
+			Hosts []string `getoptions:"min=x"`
+		}
+		for _, ptr := range []interface{}{
+			bindConfig{},
+			(*bindConfig)(nil),
+			new(string),
+			&unexported{},
+			&unsupported{},
+			&unknownTag{},
+			&badMin{},
+		} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("BindStruct(%T) didn't panic", ptr)
+					}
+				}()
+				New().BindStruct(ptr)
+			}()
+		}
+	})
+}
+
+func TestKebabCase(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Host":       "host",
+		"DryRun":     "dry-run",
+		"HTTPPort":   "http-port",
+		"IDs":        "ids",
+		"HTTPServer": "http-server",
+		"ID":         "id",
+		"LogLevel":   "log-level",
+	} {
+		if got := kebabCase(input); got != expected {
+			t.Errorf("kebabCase(%s): got %s, expected %s", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+/*
+Package compat - reports the breaking changes between two versions of a go-getoptions program.
+
+The versions are compared using the schema returned by opt.Schema(), usually saved as JSON on each release:
+
+  old, _ := ioutil.ReadFile("schema-v1.json")
+  new, _ := json.Marshal(opt.Schema())
+  changes, err := compat.CompareJSON(old, new)
+  if err != nil {
+      return err
+  }
+  for _, change := range changes {
+      fmt.Println(change)
+  }
+  if len(changes) > 0 {
+      os.Exit(1)
+  }
+
+Commands accept the options defined on their parents, so an option moved from a command to one of its parents is not reported.
+Changes to an option are reported once, on the command that defines it.
+
+Options and commands that are deprecated in the old version, see opt.Deprecated and opt.SetDeprecated,
+can be removed without being reported.
+*/
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zhizh/go-getoptions"
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// Kind - Kind of breaking change.
+type Kind int
+
+// Breaking change kinds
+const (
+	RemovedCommand Kind = iota
+	RemovedOption
+	RemovedAlias
+	ChangedType
+	BecameRequired // Includes new required options
+	ChangedEnvVar  // Includes removed env vars
+)
+
+// Change - Breaking change between two versions of the program.
+type Change struct {
+	Kind    Kind
+	Command string // Command path, for example: "mygit log"
+	Option  string // Option name, empty for command changes
+	Alias   string // Removed alias
+	Old     string // Old type or env var
+	New     string // New type or env var
+}
+
+// String - Returns the description of the change prefixed by the command path.
+func (c Change) String() string {
+	msg := ""
+	switch c.Kind {
+	case RemovedCommand:
+		msg = text.CompatRemovedCommand
+	case RemovedOption:
+		msg = fmt.Sprintf(text.CompatRemovedOption, c.Option)
+	case RemovedAlias:
+		msg = fmt.Sprintf(text.CompatRemovedAlias, c.Alias, c.Option)
+	case ChangedType:
+		msg = fmt.Sprintf(text.CompatChangedType, c.Option, c.Old, c.New)
+	case BecameRequired:
+		msg = fmt.Sprintf(text.CompatBecameRequired, c.Option)
+	case ChangedEnvVar:
+		msg = fmt.Sprintf(text.CompatChangedEnvVar, c.Option, c.Old, c.New)
+	}
+	return fmt.Sprintf("%s: %s", c.Command, msg)
+}
+
+// CompareJSON - Returns the breaking changes between two JSON encoded schemas.
+func CompareJSON(old, new []byte) ([]Change, error) {
+	var oldSchema, newSchema getoptions.Schema
+	err := json.Unmarshal(old, &oldSchema)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(new, &newSchema)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(&oldSchema, &newSchema), nil
+}
+
+// Compare - Returns the breaking changes from the old to the new schema.
+// Changes are listed in command order, the program first, and in option name order.
+func Compare(old, new *getoptions.Schema) []Change {
+	return compare(nil, old, new, nil, nil)
+}
+
+// compare - Compares the command and its children.
+// The inherited options are the options defined on the parents of each version.
+func compare(path []string, old, new *getoptions.Schema, oldInherited, newInherited map[string]getoptions.SchemaOption) []Change {
+	path = append(append([]string{}, path...), old.Name)
+	command := strings.Join(path, " ")
+	oldOptions := effectiveOptions(old, oldInherited)
+	newOptions := effectiveOptions(new, newInherited)
+
+	// Only the options defined on the command are checked, the inherited ones are checked on the parent.
+	changes := []Change{}
+	for _, oldOpt := range sortedOptions(old.Options) {
+		newOpt, ok := newOptions[oldOpt.Name]
+		if !ok {
+			if !oldOpt.Deprecated {
+				changes = append(changes, Change{Kind: RemovedOption, Command: command, Option: oldOpt.Name})
+			}
+			continue
+		}
+		for _, alias := range oldOpt.Aliases {
+			if !oldOpt.Deprecated && !contains(newOpt.Aliases, alias) {
+				changes = append(changes, Change{Kind: RemovedAlias, Command: command, Option: oldOpt.Name, Alias: alias})
+			}
+		}
+		if oldOpt.Type != newOpt.Type {
+			changes = append(changes, Change{Kind: ChangedType, Command: command, Option: oldOpt.Name, Old: oldOpt.Type, New: newOpt.Type})
+		}
+		if !oldOpt.IsRequired && newOpt.IsRequired {
+			changes = append(changes, Change{Kind: BecameRequired, Command: command, Option: oldOpt.Name})
+		}
+		if oldOpt.EnvVar != "" && oldOpt.EnvVar != newOpt.EnvVar {
+			changes = append(changes, Change{Kind: ChangedEnvVar, Command: command, Option: oldOpt.Name, Old: oldOpt.EnvVar, New: newOpt.EnvVar})
+		}
+	}
+	for _, newOpt := range sortedOptions(new.Options) {
+		if _, ok := oldOptions[newOpt.Name]; !ok && newOpt.IsRequired {
+			changes = append(changes, Change{Kind: BecameRequired, Command: command, Option: newOpt.Name})
+		}
+	}
+
+	newCommands := map[string]*getoptions.Schema{}
+	for _, c := range new.Commands {
+		newCommands[c.Name] = c
+	}
+	for _, oldCommand := range old.Commands {
+		newCommand, ok := newCommands[oldCommand.Name]
+		if !ok {
+			if oldCommand.Deprecated {
+				continue
+			}
+			changes = append(changes, Change{Kind: RemovedCommand, Command: strings.Join(append(path, oldCommand.Name), " ")})
+			continue
+		}
+		changes = append(changes, compare(path, oldCommand, newCommand, oldOptions, newOptions)...)
+	}
+	return changes
+}
+
+// effectiveOptions - Returns the options accepted by the command indexed by name.
+func effectiveOptions(schema *getoptions.Schema, inherited map[string]getoptions.SchemaOption) map[string]getoptions.SchemaOption {
+	options := map[string]getoptions.SchemaOption{}
+	for name, opt := range inherited {
+		options[name] = opt
+	}
+	for _, opt := range schema.Options {
+		options[opt.Name] = opt
+	}
+	return options
+}
+
+func sortedOptions(options []getoptions.SchemaOption) []getoptions.SchemaOption {
+	list := append([]getoptions.SchemaOption{}, options...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
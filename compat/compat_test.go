@@ -0,0 +1,163 @@
+package compat
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/zhizh/go-getoptions"
+)
+
+func v1() *getoptions.GetOpt {
+	opt := getoptions.New().Self("tool", "")
+	opt.Bool("debug", false, opt.Alias("d"))
+	opt.String("profile", "default", opt.GetEnv("TOOL_PROFILE"))
+	opt.Int("timeout", 10)
+	log := opt.NewCommand("log", "Show logs")
+	log.Int("lines", 10, opt.Alias("n"))
+	log.String("format", "text")
+	log.String("since", "")
+	log.NewCommand("tail", "Follow logs")
+	opt.NewCommand("show", "Show objects")
+	return opt
+}
+
+type level string
+
+func (l *level) Set(s string) error { *l = level(s); return nil }
+func (l *level) String() string     { return string(*l) }
+func (l *level) Type() string       { return "level" }
+
+type color string
+
+func (c *color) Set(s string) error { *c = color(s); return nil }
+func (c *color) String() string     { return string(*c) }
+func (c *color) Type() string       { return "color" }
+
+func TestCompare(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		old := v1()
+		got := Compare(old.Schema(), v1().Schema())
+		if len(got) != 0 {
+			t.Errorf("Unexpected changes: %v", got)
+		}
+	})
+
+	t.Run("breaking changes", func(t *testing.T) {
+		opt := getoptions.New().Self("tool", "")
+		opt.Bool("debug", false)                                // alias removed
+		opt.String("profile", "default", opt.GetEnv("PROFILE")) // env var changed
+		opt.String("since", "")                                 // moved from log to the program
+		opt.String("timeout", "10s")                            // type changed
+		log := opt.NewCommand("log", "Show logs")
+		log.Int("lines", 10, opt.Alias("n"))
+		log.String("format", "text", opt.Required()) // became required
+		log.String("output", "", opt.Required())     // new required option
+		tail := log.NewCommand("tail", "Follow logs")
+		tail.Bool("quiet", false) // new optional option
+
+		got := []string{}
+		for _, change := range Compare(v1().Schema(), opt.Schema()) {
+			got = append(got, change.String())
+		}
+		expected := []string{
+			"tool: alias 'd' of option 'debug' removed",
+			"tool: option 'profile' env var changed from 'TOOL_PROFILE' to 'PROFILE'",
+			"tool: option 'timeout' type changed from 'int' to 'string'",
+			"tool log: option 'format' is now required",
+			"tool log: option 'output' is now required",
+			"tool show: command removed",
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got:\n%q\nexpected:\n%q", got, expected)
+		}
+	})
+
+	t.Run("removed options", func(t *testing.T) {
+		opt := getoptions.New().Self("tool", "")
+		opt.Bool("debug", false, opt.Alias("d"))
+		opt.String("profile", "default", opt.GetEnv("TOOL_PROFILE"))
+		log := opt.NewCommand("log", "Show logs")
+		log.Int("lines", 10, opt.Alias("n"))
+		log.String("format", "text")
+		log.String("since", "")
+		log.NewCommand("tail", "Follow logs")
+		opt.NewCommand("show", "Show objects")
+
+		got := Compare(v1().Schema(), opt.Schema())
+		expected := []Change{{Kind: RemovedOption, Command: "tool", Option: "timeout"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got:\n%#v\nexpected:\n%#v", got, expected)
+		}
+	})
+
+	t.Run("deprecated removals", func(t *testing.T) {
+		old := getoptions.New().Self("tool", "")
+		old.Bool("debug", false, old.Alias("d"), old.Deprecated(""))
+		old.Int("timeout", 10, old.Deprecated("use --deadline"))
+		old.NewCommand("show", "Show objects").SetDeprecated("")
+		old.NewCommand("log", "Show logs")
+
+		opt := getoptions.New().Self("tool", "")
+		opt.Bool("debug", false)
+		opt.NewCommand("log", "Show logs")
+
+		got := Compare(old.Schema(), opt.Schema())
+		if len(got) != 0 {
+			t.Errorf("Unexpected changes: %v", got)
+		}
+	})
+
+	t.Run("user defined types", func(t *testing.T) {
+		var l level
+		var c color
+		old := getoptions.New().Self("tool", "")
+		old.Var(&l, "mode")
+		opt := getoptions.New().Self("tool", "")
+		opt.Var(&c, "mode")
+
+		got := Compare(old.Schema(), opt.Schema())
+		expected := []Change{{Kind: ChangedType, Command: "tool", Option: "mode", Old: "value:level", New: "value:color"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got:\n%#v\nexpected:\n%#v", got, expected)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		old, err := json.Marshal(v1().Schema())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		opt := v1()
+		opt.NewCommand("new", "")
+		new, err := json.Marshal(opt.Schema())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		got, err := CompareJSON(old, new)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("Unexpected changes: %v", got)
+		}
+		// Removing the command is reported
+		got, err = CompareJSON(new, old)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := []Change{{Kind: RemovedCommand, Command: "tool new"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got:\n%#v\nexpected:\n%#v", got, expected)
+		}
+
+		_, err = CompareJSON([]byte("{"), new)
+		if err == nil {
+			t.Errorf("Expected error for invalid JSON")
+		}
+		_, err = CompareJSON(old, []byte("{"))
+		if err == nil {
+			t.Errorf("Expected error for invalid JSON")
+		}
+	})
+}
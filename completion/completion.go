@@ -9,10 +9,11 @@
 package completion
 
 import (
+	"context"
 	"io/ioutil"
 	"log"
-	"regexp"
 	"strings"
+	"time"
 )
 
 // Debug - Debug logger set to ioutil.Discard by default
@@ -34,6 +35,8 @@ Example:
   ├ show
   │ ├ --help
   │ ├ --dir=<dir-completion>
+  │ ├ --config=<file-completion (*.yaml)>
+  │ ├ --branch=<callback-completion>
   │ └ <file-completion>
   ├ --help
   └ --version
@@ -43,9 +46,49 @@ type Node struct {
 	Kind     kind   // Kind of node.
 	Children []*Node
 	Entries  []string // Use as completions for OptionsNode and CustomNode Kind.
-	// TODO: Maybe add sibling completion that gets activated with = for options
+
+	// EntriesFn - Returns the entries of a CustomNode Kind at completion time, replacing Entries.
+	// Used for lists that can change after the node is created, for example the commands completed by the help command.
+	EntriesFn func() []string
+
+	// ArgCompletion - Completions for the argument of an option, indexed by the option entry (e.g. --format).
+	// Used by OptionsNode and OptionsWithCompletion Kinds to complete --format=<TAB> and --format <TAB>.
+	// The completion node can be of any kind that completes a value: FileListNode, DirListNode, CustomNode or CallbackNode.
+	ArgCompletion map[string]*Node
+
+	// Patterns - Glob patterns, as in filepath.Match, that restrict the files returned by FileListNode Kind.
+	// Directories are always returned to allow navigating into them.
+	Patterns []string
+
+	// Callback - Function that returns the completions for CallbackNode Kind.
+	Callback CallbackFn
+
+	// Hidden - Excludes a CommandNode Kind from the completions of its parent.
+	// The command options and arguments are still completed once the command is typed.
+	Hidden bool
+
+	// Argument - Position, starting at 1, of the positional argument completed by the node.
+	// The node only completes the operand typed at that position, 0 completes any position.
+	// Used by FileListNode, DirListNode, CustomNode and CallbackNode Kinds.
+	Argument int
+
+	// Variadic - The node also completes every position after Argument.
+	Variadic bool
+
+	// IgnoreCase - Match the prefix ignoring case.
+	// Used by FileListNode and DirListNode Kinds.
+	IgnoreCase bool
 }
 
+// CallbackFn - Function called at completion time with the word being completed.
+// The results are filtered by prefix so the function can return all its entries.
+// The context is cancelled after CallbackTimeout.
+type CallbackFn func(ctx context.Context, prefix string) []string
+
+// CallbackTimeout - Maximum time to wait for a CallbackFn to return.
+// When the timeout is reached the callback results are discarded so a slow callback can't hang the shell.
+var CallbackTimeout = 2 * time.Second
+
 // CompletionType -
 type kind int
 
@@ -58,7 +101,7 @@ const (
 
 	// FileListNode - Regular file completion you would expect.
 	// Name used as the dir to start completing results from.
-	// TODO: Allow ignore case.
+	// Files can be restricted to the ones matching the node Patterns.
 	FileListNode
 
 	// OptionsNode - Only enabled if prefix starts with -
@@ -69,6 +112,13 @@ const (
 
 	// CustomNode -
 	CustomNode
+
+	// CallbackNode - Completions returned at completion time by the node Callback.
+	CallbackNode
+
+	// DirListNode - Directory completion.
+	// Name used as the dir to start completing results from.
+	DirListNode
 )
 
 // NewNode -
@@ -77,12 +127,28 @@ func NewNode(name string, kind kind, entries []string) *Node {
 		entries = []string{}
 	}
 	return &Node{
-		Name:    name,
-		Kind:    kind,
-		Entries: entries,
+		Name:          name,
+		Kind:          kind,
+		Entries:       entries,
+		ArgCompletion: map[string]*Node{},
 	}
 }
 
+// NewCallbackNode - Returns a CallbackNode that gets its completions from fn.
+func NewCallbackNode(name string, fn CallbackFn) *Node {
+	node := NewNode(name, CallbackNode, nil)
+	node.Callback = fn
+	return node
+}
+
+// GetEntries - Returns the node entries, calling EntriesFn when set.
+func (n *Node) GetEntries() []string {
+	if n.EntriesFn != nil {
+		return n.EntriesFn()
+	}
+	return n.Entries
+}
+
 // AddChild -
 // TODO: Probably make sure that the name is not already in use since we find them by name.
 func (n *Node) AddChild(node *Node) {
@@ -97,8 +163,8 @@ func (n *Node) SelfCompletions(prefix string) []string {
 			Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, []string{n.Name})
 			return []string{n.Name}
 		}
-	case FileListNode:
-		files, _ := listDir(n.Name, prefix)
+	case FileListNode, DirListNode:
+		files, _ := listDir(n.Name, prefix, listFilter{dirsOnly: n.Kind == DirListNode, patterns: n.Patterns, ignoreCase: n.IgnoreCase})
 		if strings.HasPrefix(prefix, ".") {
 			Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, files)
 			return files
@@ -122,8 +188,14 @@ func (n *Node) SelfCompletions(prefix string) []string {
 			return ee
 		}
 	case CustomNode:
-		sortForCompletion(n.Entries)
-		ee := keepByPrefix(n.Entries, prefix)
+		entries := n.GetEntries()
+		sortForCompletion(entries)
+		ee := keepByPrefix(entries, prefix)
+		Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, ee)
+		return ee
+	case CallbackNode:
+		ee := keepByPrefix(runCallback(n.Callback, prefix), prefix)
+		sortForCompletion(ee)
 		Debug.Printf("SelfCompletions - node: %s > %v\n", n.Name, ee)
 		return ee
 	}
@@ -131,19 +203,34 @@ func (n *Node) SelfCompletions(prefix string) []string {
 	return []string{}
 }
 
+// completesOperand - Indicates if the node completes the operand at the given index, starting at 0.
+func (n *Node) completesOperand(i int) bool {
+	return n.Argument == 0 || n.Argument == i+1 || (n.Variadic && i+1 > n.Argument)
+}
+
 // Completions -
 func (n *Node) Completions(prefix string) []string {
+	return n.completions(prefix, 0)
+}
+
+// completions - Returns the completions for the prefix when the given number of operands has already been typed.
+func (n *Node) completions(prefix string, operands int) []string {
 	results := []string{}
 	stringNodeResults := []string{}
 	optionResults := []string{}
 	for _, child := range n.Children {
 		switch child.Kind {
 		case CommandNode:
+			if child.Hidden {
+				continue
+			}
 			stringNodeResults = append(stringNodeResults, child.SelfCompletions(prefix)...)
 		case OptionsNode, OptionsWithCompletion:
 			optionResults = append(optionResults, child.SelfCompletions(prefix)...)
 		default:
-			results = append(results, child.SelfCompletions(prefix)...)
+			if child.completesOperand(operands) {
+				results = append(results, child.SelfCompletions(prefix)...)
+			}
 		}
 	}
 	sortForCompletion(results)
@@ -155,6 +242,26 @@ func (n *Node) Completions(prefix string) []string {
 	return r
 }
 
+// runCallback - Runs fn and returns its results, or an empty list if it doesn't return within CallbackTimeout.
+func runCallback(fn CallbackFn, prefix string) []string {
+	if fn == nil {
+		return []string{}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), CallbackTimeout)
+	defer cancel()
+	c := make(chan []string, 1)
+	go func() {
+		c <- fn(ctx, prefix)
+	}()
+	select {
+	case results := <-c:
+		return results
+	case <-ctx.Done():
+		Debug.Printf("runCallback - timeout after %s\n", CallbackTimeout)
+		return []string{}
+	}
+}
+
 // GetChildByName - Traverses to the children and returns the first one to match name.
 func (n *Node) GetChildByName(name string) *Node {
 	for _, child := range n.Children {
@@ -165,6 +272,21 @@ func (n *Node) GetChildByName(name string) *Node {
 	return NewNode("", Root, []string{})
 }
 
+// GetArgCompletion - Returns the node that completes the argument of the given option entry.
+// It looks into the option children of the node (OptionsNode and OptionsWithCompletion).
+func (n *Node) GetArgCompletion(entry string) (*Node, bool) {
+	for _, child := range n.Children {
+		if child.Kind != OptionsNode && child.Kind != OptionsWithCompletion {
+			continue
+		}
+		if node, ok := child.ArgCompletion[entry]; ok {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// GetChildrenByKind - Returns all the direct children of the given kind.
 func (n *Node) GetChildrenByKind(kind kind) []*Node {
 	children := []*Node{}
 	for _, child := range n.Children {
@@ -198,12 +320,24 @@ func discardByPrefix(list []string, prefix string) []string {
 }
 
 // CompLineComplete - Given a compLine (get it with os.Getenv("COMP_LINE")) it returns a list of completions.
+// The compLine is split into words following the shell quoting rules and the completions are escaped
+// to replace the last, possibly quoted, word.
+// To complete in the middle of the line, pass the compLine up to the cursor (os.Getenv("COMP_POINT")).
 func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
-	// TODO: This split might not consider files that have spaces in them.
-	re := regexp.MustCompile(`\s+`)
-	compLineParts := re.Split(compLine, -1)
+	words, quote := SplitWords(compLine)
+	results := []string{}
+	for _, e := range n.completeWords(lastWasOption, words, 0) {
+		results = append(results, EscapeWord(e, quote))
+	}
+	return results
+}
+
+// completeWords - Returns the unescaped completions for the last word.
+// The first word is the executable or command name.
+// Operands is the number of positional arguments typed so far, it selects the argument completion.
+func (n *Node) completeWords(lastWasOption bool, compLineParts []string, operands int) []string {
+	compLine := strings.Join(compLineParts, " ")
 
-	// return compLineParts
 	if len(compLineParts) == 0 || compLineParts[0] == "" {
 		Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Empty compLineParts\n", n.Name, compLine, []string{})
 		return []string{}
@@ -216,7 +350,7 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 	if len(compLineParts) >= 1 {
 		current := compLineParts[0]
 
-		cc := n.Completions(current)
+		cc := n.completions(current, operands)
 		if len(compLineParts) == 1 && len(cc) > 1 {
 			Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Multiple completions for this compLine\n", n.Name, compLine, cc)
 			return cc
@@ -226,13 +360,28 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 		if child.Kind == CommandNode && child.Name == current {
 			Debug.Printf("CompLineComplete - node: %s, compLine %s - Recursing into command %s\n", n.Name, compLine, current)
 			// Recurse into the child node's completion
-			return child.CompLineComplete(false, strings.Join(compLineParts, " "))
+			return child.completeWords(false, compLineParts, 0)
+		}
+		// Check if the current is an option with its argument: --option=arg
+		if i := strings.Index(current, "="); i > 0 && len(compLineParts) == 1 && strings.HasPrefix(current, "-") {
+			if argNode, ok := n.GetArgCompletion(current[:i]); ok {
+				Debug.Printf("CompLineComplete - node: %s, compLine %s - Completing argument for %s\n", n.Name, compLine, current[:i])
+				return argNode.SelfCompletions(current[i+1:])
+			}
+		}
+		// The current is the argument of the previous option, it is not an operand
+		if lastWasOption && len(compLineParts) > 1 {
+			Debug.Printf("CompLineComplete - node: %s, compLine %s - Previous was option %s, recursing to self\n", n.Name, compLine, current)
+			return n.completeWords(false, compLineParts, operands)
 		}
 		// Check if the current fully matches an option
 		list := n.GetChildrenByKind(OptionsNode)
 		list = append(list, n.GetChildrenByKind(CustomNode)...)
 		for _, child := range list {
-			for _, e := range child.Entries {
+			if !child.completesOperand(operands) {
+				continue
+			}
+			for _, e := range child.GetEntries() {
 				if current == e {
 					if len(compLineParts) == 1 {
 						Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Fully Matched Option/Custom\n", n.Name, compLine, current)
@@ -240,7 +389,7 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched Option/Custom %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+					return n.completeWords(false, compLineParts, nextOperand(child, operands))
 				}
 			}
 		}
@@ -248,29 +397,43 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 		list = n.GetChildrenByKind(OptionsWithCompletion)
 		list = append(list, n.GetChildrenByKind(CustomNode)...)
 		for _, child := range list {
-			for _, e := range child.Entries {
+			if !child.completesOperand(operands) {
+				continue
+			}
+			for _, e := range child.GetEntries() {
 				if current == e {
 					if len(compLineParts) == 1 {
 						Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Fully Matched Option/Custom\n", n.Name, compLine, current)
 						return []string{current}
 					}
+					// Complete the argument of the option
+					if argNode, ok := n.GetArgCompletion(current); ok && len(compLineParts) == 2 {
+						Debug.Printf("CompLineComplete - node: %s, compLine %s - Completing argument for %s\n", n.Name, compLine, current)
+						return argNode.SelfCompletions(compLineParts[1])
+					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched Option/Custom %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(true, strings.Join(compLineParts, " "))
+					if child.Kind == CustomNode {
+						return n.completeWords(false, compLineParts, operands+1)
+					}
+					return n.completeWords(true, compLineParts, operands)
 				}
 				if strings.HasPrefix(current, e+"=") {
 					if len(compLineParts) == 1 {
 						Debug.Printf("CompLineComplete - node: %s, compLine %s > %v - Fully Matched Option/Custom with =\n", n.Name, compLine, current)
-						return n.Completions(current)
+						return n.completions(current, operands)
 					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched Option/Custom  with = %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+					return n.completeWords(false, compLineParts, nextOperand(child, operands))
 				}
 			}
 		}
 		// Get FileList completions after all other completions
-		for _, child := range n.GetChildrenByKind(FileListNode) {
+		for _, child := range append(n.GetChildrenByKind(FileListNode), n.GetChildrenByKind(DirListNode)...) {
+			if !child.completesOperand(operands) {
+				continue
+			}
 			cc := child.SelfCompletions(current)
 			for _, e := range cc {
 				if current == e {
@@ -280,7 +443,7 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 					}
 					Debug.Printf("CompLineComplete - node: %s, compLine %s - Fully matched File %s, recursing to self\n", n.Name, compLine, current)
 					// Recurse into the node self completion
-					return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+					return n.completeWords(false, compLineParts, operands+1)
 				}
 			}
 		}
@@ -288,18 +451,30 @@ func (n *Node) CompLineComplete(lastWasOption bool, compLine string) []string {
 		// Doesn't match anything but previous arg was an option
 		if lastWasOption {
 			Debug.Printf("CompLineComplete - node: %s, compLine %s - Previous was option %s, recursing to self\n", n.Name, compLine, current)
-			if len(compLineParts) == 1 {
-				return []string{current}
-			}
-			return n.CompLineComplete(false, strings.Join(compLineParts, " "))
+			return []string{current}
+		}
+
+		// Doesn't match anything, but it is a positional argument that has already been typed
+		if len(compLineParts) > 1 && !strings.HasPrefix(current, "-") {
+			Debug.Printf("CompLineComplete - node: %s, compLine %s - Operand %s, recursing to self\n", n.Name, compLine, current)
+			return n.completeWords(false, compLineParts, operands+1)
 		}
 
 		// Return a partial match
 		Debug.Printf("CompLineComplete - node: %s, compLine %s - Partial match %s\n", n.Name, compLine, current)
-		return n.Completions(current)
+		return n.completions(current, operands)
 	}
 
 	Debug.Printf("CompLineComplete - node: %s, compLine %s > [] - Return all results\n", n.Name, compLine)
 	// No partial request, return all results
-	return n.Completions("")
+	return n.completions("", operands)
+}
+
+// nextOperand - Returns the number of operands typed after matching a word with an entry of the node.
+// Custom entries are operands, option entries are not.
+func nextOperand(n *Node, operands int) int {
+	if n.Kind == CustomNode {
+		return operands + 1
+	}
+	return operands
 }
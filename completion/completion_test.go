@@ -10,9 +10,11 @@ package completion
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func setupLogging() *bytes.Buffer {
@@ -28,7 +30,9 @@ func TestGetChildNames(t *testing.T) {
 	// Tree setup
 	rootNode := NewNode("executable", Root, nil)
 	rootNode.AddChild(NewNode("options", OptionsNode, []string{"--version", "--help", "-v", "-h"}))
-	rootNode.AddChild(NewNode("options", OptionsWithCompletion, []string{"--profile", "-p"}))
+	optionsWithCompletion := NewNode("options", OptionsWithCompletion, []string{"--profile", "-p", "--color"})
+	optionsWithCompletion.ArgCompletion["--color"] = NewNode("color", CustomNode, []string{"never", "auto", "always"})
+	rootNode.AddChild(optionsWithCompletion)
 
 	logNode := NewNode("log", CommandNode, nil)
 	rootNode.AddChild(logNode)
@@ -57,7 +61,7 @@ func TestGetChildNames(t *testing.T) {
 		{"get commands", rootNode, "", []string{"log", "logger", "show"}},
 		{"get commands", rootNode, "log", []string{"log", "logger"}},
 		{"get commands", rootNode, "show", []string{"show"}},
-		{"get options", rootNode, "-", []string{"-h", "--help", "-p", "--profile", "-v", "--version"}},
+		{"get options", rootNode, "-", []string{"--color", "-h", "--help", "-p", "--profile", "-v", "--version"}},
 		{"get options", rootNode, "-h", []string{"-h"}},
 		{"get commands", rootNode.GetChildByName("x"), "", []string{}},
 		{"filter out hidden files", rootNode.GetChildByName("log"), "", []string{"sublog", "aFile1", "aFile2", "bDir1/", "bDir2/", "cFile1", "cFile2"}},
@@ -103,7 +107,7 @@ func TestGetChildNames(t *testing.T) {
 		{"top level", rootNode, "./executable log", []string{"log", "logger"}},
 		{"top level", rootNode, "./executable  log", []string{"log", "logger"}},
 		{"top level", rootNode, "./executable sh", []string{"show"}},
-		{"options", rootNode, "./executable -", []string{"-h", "--help", "-p", "--profile", "-v", "--version"}},
+		{"options", rootNode, "./executable -", []string{"--color", "-h", "--help", "-p", "--profile", "-v", "--version"}},
 		{"options", rootNode, "./executable -h", []string{"-h"}},
 		{"options", rootNode, "./executable -h ", []string{"log", "logger", "show"}},
 		{"options", rootNode, "./executable  -h  l", []string{"log", "logger"}},
@@ -116,6 +120,11 @@ func TestGetChildNames(t *testing.T) {
 		{"options", rootNode, "./executable  --profile=dev", []string{}},
 		{"options", rootNode, "./executable  --profile dev", []string{"dev"}},
 		{"options", rootNode, "./executable  --profile dev  l", []string{"log", "logger"}},
+		{"option argument", rootNode, "./executable --color=", []string{"always", "auto", "never"}},
+		{"option argument", rootNode, "./executable --color=a", []string{"always", "auto"}},
+		{"option argument", rootNode, "./executable --color ", []string{"always", "auto", "never"}},
+		{"option argument", rootNode, "./executable --color n", []string{"never"}},
+		{"option argument", rootNode, "./executable --color never l", []string{"log", "logger"}},
 		{"command", rootNode, "./executable log ", []string{"sublog", "aFile1", "aFile2", "bDir1/", "bDir2/", "cFile1", "cFile2"}},
 		{"command", rootNode, "./executable log bDir1/f", []string{"bDir1/file"}},
 		{"command", rootNode, "./executable log bDir1/file ", []string{"sublog", "aFile1", "aFile2", "bDir1/", "bDir2/", "cFile1", "cFile2"}},
@@ -137,3 +146,47 @@ func TestGetChildNames(t *testing.T) {
 		})
 	}
 }
+
+func TestCallbackNode(t *testing.T) {
+	node := NewCallbackNode("branches", func(ctx context.Context, prefix string) []string {
+		return []string{"main", "feature-b", "feature-a", prefix + "-passed"}
+	})
+	got := node.SelfCompletions("f")
+	expected := []string{"f-passed", "feature-a", "feature-b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got: %v, expected: %v", got, expected)
+	}
+
+	if got := NewCallbackNode("nil", nil).SelfCompletions(""); len(got) != 0 {
+		t.Errorf("Unexpected completions: %v", got)
+	}
+
+	defer func(d time.Duration) { CallbackTimeout = d }(CallbackTimeout)
+	CallbackTimeout = 10 * time.Millisecond
+	node = NewCallbackNode("slow", func(ctx context.Context, prefix string) []string {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return []string{"main"}
+	})
+	if got := node.SelfCompletions(""); len(got) != 0 {
+		t.Errorf("Unexpected completions: %v", got)
+	}
+}
+
+func TestFileNodeFilters(t *testing.T) {
+	node := NewNode("test/test_tree", DirListNode, nil)
+	if got := node.SelfCompletions(""); !reflect.DeepEqual(got, []string{"bDir1/", "bDir2/"}) {
+		t.Errorf("Unexpected completions: %v", got)
+	}
+
+	node = NewNode("test/test_tree", FileListNode, nil)
+	node.Patterns = []string{"?File2"}
+	if got := node.SelfCompletions(""); !reflect.DeepEqual(got, []string{"aFile2", "bDir1/", "bDir2/", "cFile2"}) {
+		t.Errorf("Unexpected completions: %v", got)
+	}
+
+	node.IgnoreCase = true
+	if got := node.SelfCompletions("CF"); !reflect.DeepEqual(got, []string{"cFile2"}) {
+		t.Errorf("Unexpected completions: %v", got)
+	}
+}
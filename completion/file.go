@@ -113,9 +113,47 @@ func sortForCompletion(list []string) {
 		})
 }
 
-// listDir - Given a dir and a prefix returns a list of files in the dir filtered by their prefix.
+// listFilter - Restricts the entries returned by listDir.
+type listFilter struct {
+	dirsOnly   bool     // Only return directories
+	patterns   []string // Only return the files matching one of the glob patterns, directories are always returned
+	ignoreCase bool     // Match the prefix ignoring case
+}
+
+// keep - Indicates if the entry passes the filter.
+func (f listFilter) keep(name, prefix string, isDir bool) bool {
+	if f.ignoreCase {
+		if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			return false
+		}
+	} else if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if isDir {
+		return true
+	}
+	if f.dirsOnly {
+		return false
+	}
+	if len(f.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range f.patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if f.ignoreCase {
+			if ok, _ := filepath.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listDir - Given a dir and a prefix returns a list of files in the dir filtered by their prefix and the given filter.
 // NOTE: dot (".") is a valid dirname.
-func listDir(dirname string, prefix string) ([]string, error) {
+func listDir(dirname string, prefix string, filter listFilter) ([]string, error) {
 	filenames := []string{}
 	usedDirname := dirname
 	dir := ""
@@ -137,7 +175,7 @@ func listDir(dirname string, prefix string) ([]string, error) {
 	}
 	for _, fi := range fileInfoList {
 		name := fi.Name()
-		if !strings.HasPrefix(name, prefix) {
+		if !filter.keep(name, prefix, fi.IsDir()) {
 			continue
 		}
 		if dirname != usedDirname {
@@ -35,7 +35,7 @@ func TestListDir(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, gotErr := listDir(tt.dirname, tt.prefix)
+			got, gotErr := listDir(tt.dirname, tt.prefix, listFilter{})
 			if gotErr == nil && tt.err != "" {
 				t.Errorf("getFileList() got = '%v', want '%v'", gotErr, tt.err)
 			}
@@ -53,6 +53,37 @@ func TestListDir(t *testing.T) {
 	}
 }
 
+func TestListDirFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		dirname string
+		prefix  string
+		filter  listFilter
+		list    []string
+	}{
+		{"dirs only", "test/test_tree", "", listFilter{dirsOnly: true}, []string{"bDir1/", "bDir2/"}},
+		{"dirs only single", "test/test_tree", "bDir2", listFilter{dirsOnly: true}, []string{"bDir2/", "bDir2/ "}},
+		{"dirs only no match", "test/test_tree", "a", listFilter{dirsOnly: true}, []string{}},
+		{"pattern", "test/test_tree", "", listFilter{patterns: []string{"*1"}}, []string{"aFile1", "bDir1/", "bDir2/", "cFile1"}},
+		{"patterns", "test/test_tree", "a", listFilter{patterns: []string{"*1", "aFile?"}}, []string{"aFile1", "aFile2"}},
+		{"pattern subdir", "test/test_tree", "bDir1/", listFilter{patterns: []string{"f*"}}, []string{"bDir1/file"}},
+		{"ignore case", "test/test_tree", "AF", listFilter{ignoreCase: true}, []string{"aFile1", "aFile2"}},
+		{"ignore case pattern", "test/test_tree", "", listFilter{ignoreCase: true, patterns: []string{"CFILE*"}}, []string{"bDir1/", "bDir2/", "cFile1", "cFile2"}},
+		{"case sensitive", "test/test_tree", "AF", listFilter{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := listDir(tt.dirname, tt.prefix, tt.filter)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.list) {
+				t.Errorf("listDir() got = %v, want %v", got, tt.list)
+			}
+		})
+	}
+}
+
 func TestSortForCompletion(t *testing.T) {
 	tests := []struct {
 		name   string
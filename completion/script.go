@@ -0,0 +1,316 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zhizh/go-getoptions/option"
+)
+
+// ScriptCommand - Description of a command used to generate static completion scripts.
+type ScriptCommand struct {
+	Name        string
+	Description string
+	Options     []ScriptOption
+	Commands    []*ScriptCommand
+	Custom      []string // Custom completion list for the command arguments
+	Files       bool     // Indicates if the command arguments are completed with file names
+	Patterns    []string // Glob patterns that restrict the completed file names
+	Dirs        bool     // Indicates if the command arguments are completed with directory names
+	Bundling    bool     // Indicates if single letter options can be bundled: -abc
+}
+
+// ScriptOption - Description of an option used to generate static completion scripts.
+type ScriptOption struct {
+	Aliases     []string // Option aliases without leading dashes
+	Description string
+	HasArg      bool     // Indicates if the option requires an argument
+	OptionalArg bool     // Indicates if the option argument is optional, only used when HasArg is set
+	ArgName     string   // Argument name shown by the shell
+	Repeatable  bool     // Indicates if the option can be passed multiple times
+	Values      []string // Valid values for the option argument
+	Files       bool     // Indicates if the option argument is completed with file names
+	Patterns    []string // Glob patterns that restrict the completed file names
+	Dirs        bool     // Indicates if the option argument is completed with directory names
+}
+
+// sortScriptCommand - Sorts the options and commands of the tree for deterministic output.
+func sortScriptCommand(cmd *ScriptCommand) {
+	sort.Slice(cmd.Options, func(i, j int) bool { return cmd.Options[i].Aliases[0] < cmd.Options[j].Aliases[0] })
+	sort.Slice(cmd.Commands, func(i, j int) bool { return cmd.Commands[i].Name < cmd.Commands[j].Name })
+	for _, c := range cmd.Commands {
+		sortScriptCommand(c)
+	}
+}
+
+// firstLine - Returns the first line of a possibly multiline description.
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
+
+var nonIdentifierRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// functionName - Returns a valid shell function name for the command path.
+func functionName(parts ...string) string {
+	return "_" + nonIdentifierRegex.ReplaceAllString(strings.Join(parts, "_"), "_")
+}
+
+// zshQuote - Single quotes a string for zsh.
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// zshEscape - Escapes the characters with special meaning in an _arguments spec.
+func zshEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`)
+	return r.Replace(s)
+}
+
+// zshAction - Returns the _arguments action that completes the given values, directories or files.
+func zshAction(values []string, files, dirs bool, patterns []string) string {
+	if len(values) > 0 {
+		escaped := []string{}
+		for _, v := range values {
+			escaped = append(escaped, strings.NewReplacer(`\`, `\\`, " ", `\ `, "(", `\(`, ")", `\)`, ":", `\:`).Replace(v))
+		}
+		return fmt.Sprintf("(%s)", strings.Join(escaped, " "))
+	}
+	if dirs {
+		return "_files -/"
+	}
+	if files {
+		switch len(patterns) {
+		case 0:
+			return "_files"
+		case 1:
+			return fmt.Sprintf(`_files -g "%s"`, patterns[0])
+		}
+		return fmt.Sprintf(`_files -g "(%s)"`, strings.Join(patterns, "|"))
+	}
+	return " "
+}
+
+// zshOptionSpecs - Returns the _arguments specs for the option.
+func zshOptionSpecs(opt ScriptOption) []string {
+	names := []string{}
+	for _, alias := range opt.Aliases {
+		name := option.Dashed(alias)
+		if opt.HasArg {
+			if len(alias) > 1 {
+				name += "="
+			} else {
+				name += "+"
+			}
+		}
+		names = append(names, name)
+	}
+	exclusion := ""
+	if opt.Repeatable {
+		exclusion = "*"
+	} else if len(opt.Aliases) > 1 {
+		list := []string{}
+		for _, alias := range opt.Aliases {
+			list = append(list, option.Dashed(alias))
+		}
+		exclusion = fmt.Sprintf("(%s)", strings.Join(list, " "))
+	}
+	spec := ""
+	if opt.Description != "" {
+		spec = fmt.Sprintf("[%s]", zshEscape(firstLine(opt.Description)))
+	}
+	if opt.HasArg {
+		separator := ":"
+		if opt.OptionalArg {
+			separator = "::"
+		}
+		spec += fmt.Sprintf("%s%s:%s", separator, zshEscape(opt.ArgName), zshAction(opt.Values, opt.Files, opt.Dirs, opt.Patterns))
+	}
+	specs := []string{}
+	for _, name := range names {
+		specs = append(specs, zshQuote(exclusion+name+spec))
+	}
+	return specs
+}
+
+// ZshScript - Returns a zsh completion script for the program.
+// The script can be sourced or placed in a directory in $fpath as `_<prog>`.
+func ZshScript(prog string, root *ScriptCommand) string {
+	sortScriptCommand(root)
+	out := fmt.Sprintf("#compdef %s\n\n", prog)
+	out += fmt.Sprintf("# zsh completion for %s\n", prog)
+	out += zshFunction([]string{prog}, root)
+	out += fmt.Sprintf("\nif [ \"$funcstack[1]\" = \"%s\" ]; then\n", functionName(prog))
+	out += fmt.Sprintf("\t%s \"$@\"\n", functionName(prog))
+	out += "else\n"
+	out += fmt.Sprintf("\tcompdef %s %s\n", functionName(prog), prog)
+	out += "fi\n"
+	return out
+}
+
+func zshFunction(path []string, cmd *ScriptCommand) string {
+	out := fmt.Sprintf("\n%s() {\n", functionName(path...))
+	args := []string{}
+	for _, opt := range cmd.Options {
+		args = append(args, zshOptionSpecs(opt)...)
+	}
+	if len(cmd.Commands) > 0 {
+		out += "\tlocal context state state_descr line\n"
+		out += "\ttypeset -A opt_args\n"
+		args = append(args, zshQuote("1: :->command"), zshQuote("*:: :->args"))
+	} else if len(cmd.Custom) > 0 || cmd.Files || cmd.Dirs {
+		args = append(args, zshQuote("*: :"+zshAction(cmd.Custom, cmd.Files, cmd.Dirs, cmd.Patterns)))
+	}
+	out += "\t_arguments -C"
+	if cmd.Bundling {
+		// Complete the options of bundled single letter options: -ab<TAB>
+		out += " -s"
+	}
+	for _, arg := range args {
+		out += " \\\n\t\t" + arg
+	}
+	out += "\n"
+	if len(cmd.Commands) > 0 {
+		out += "\tcase $state in\n"
+		out += "\tcommand)\n"
+		out += "\t\tlocal -a commands\n"
+		out += "\t\tcommands=(\n"
+		for _, c := range cmd.Commands {
+			entry := strings.ReplaceAll(c.Name, ":", `\:`)
+			if c.Description != "" {
+				entry += ":" + firstLine(c.Description)
+			}
+			out += fmt.Sprintf("\t\t\t%s\n", zshQuote(entry))
+		}
+		out += "\t\t)\n"
+		out += "\t\t_describe -t commands 'command' commands\n"
+		out += "\t\t;;\n"
+		out += "\targs)\n"
+		out += "\t\tcase $line[1] in\n"
+		for _, c := range cmd.Commands {
+			out += fmt.Sprintf("\t\t%s)\n", zshQuote(c.Name))
+			out += fmt.Sprintf("\t\t\t%s\n", functionName(append(path, c.Name)...))
+			out += "\t\t\t;;\n"
+		}
+		out += "\t\tesac\n"
+		out += "\t\t;;\n"
+		out += "\tesac\n"
+	}
+	out += "}\n"
+	for _, c := range cmd.Commands {
+		out += zshFunction(append(append([]string{}, path...), c.Name), c)
+	}
+	return out
+}
+
+// fishQuote - Single quotes a string for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// FishScript - Returns a fish completion script for the program.
+// The script can be sourced or placed in ~/.config/fish/completions/<prog>.fish.
+//
+// Completions are scoped to the command path typed so far, so command options are only offered after the command.
+func FishScript(prog string, root *ScriptCommand) string {
+	sortScriptCommand(root)
+	pathFn := "__fish" + functionName(prog) + "_command_path"
+	usingFn := "__fish" + functionName(prog) + "_using_command"
+
+	paths := []string{}
+	var walk func(path []string, cmd *ScriptCommand)
+	walk = func(path []string, cmd *ScriptCommand) {
+		for _, c := range cmd.Commands {
+			p := append(append([]string{}, path...), c.Name)
+			paths = append(paths, fishQuote(strings.Join(p, " ")))
+			walk(p, c)
+		}
+	}
+	walk([]string{}, root)
+
+	out := fmt.Sprintf("# fish completion for %s\n\n", prog)
+	out += "# Prints the commands typed so far, ignoring options and arguments.\n"
+	out += fmt.Sprintf("function %s\n", pathFn)
+	out += strings.TrimRight("\tset -l commands "+strings.Join(paths, " "), " ") + "\n"
+	out += "\tset -l path\n"
+	out += "\tfor word in (commandline -opc)[2..-1]\n"
+	out += "\t\tif string match -q -- '-*' $word\n"
+	out += "\t\t\tcontinue\n"
+	out += "\t\tend\n"
+	out += "\t\tif contains -- (string join ' ' -- $path $word) $commands\n"
+	out += "\t\t\tset path $path $word\n"
+	out += "\t\tend\n"
+	out += "\tend\n"
+	out += "\tstring join ' ' -- $path\n"
+	out += "end\n\n"
+	out += fmt.Sprintf("function %s\n", usingFn)
+	out += fmt.Sprintf("\tset -l path (%s)\n", pathFn)
+	out += "\ttest \"$path\" = \"$argv\"\n"
+	out += "end\n"
+	out += fishCommand(prog, usingFn, []string{}, root)
+	return out
+}
+
+func fishCommand(prog, usingFn string, path []string, cmd *ScriptCommand) string {
+	condition := fishQuote(strings.TrimSpace(usingFn + " " + strings.Join(path, " ")))
+	prefix := fmt.Sprintf("complete -c %s -n %s", prog, condition)
+	out := "\n"
+	if len(path) > 0 {
+		out = fmt.Sprintf("\n# %s\n", strings.Join(path, " "))
+	}
+	if !cmd.Files || len(cmd.Commands) > 0 {
+		out += prefix + " -f\n"
+	}
+	if cmd.Dirs && len(cmd.Commands) == 0 {
+		out += prefix + " -f -a '(__fish_complete_directories)'\n"
+	}
+	for _, c := range cmd.Commands {
+		line := fmt.Sprintf("%s -f -a %s", prefix, fishQuote(c.Name))
+		if c.Description != "" {
+			line += " -d " + fishQuote(firstLine(c.Description))
+		}
+		out += line + "\n"
+	}
+	if len(cmd.Custom) > 0 {
+		out += fmt.Sprintf("%s -f -a %s\n", prefix, fishQuote(strings.Join(cmd.Custom, " ")))
+	}
+	for _, opt := range cmd.Options {
+		line := prefix
+		for _, alias := range opt.Aliases {
+			if len(alias) == 1 {
+				line += " -s " + fishQuote(alias)
+			} else {
+				line += " -l " + fishQuote(alias)
+			}
+		}
+		if opt.Description != "" {
+			line += " -d " + fishQuote(firstLine(opt.Description))
+		}
+		if opt.HasArg {
+			switch {
+			case len(opt.Values) > 0:
+				line += " -x -a " + fishQuote(strings.Join(opt.Values, " "))
+			case opt.Dirs:
+				line += " -x -a '(__fish_complete_directories)'"
+			case opt.Files:
+				line += " -r -F"
+			default:
+				line += " -x"
+			}
+		}
+		out += line + "\n"
+	}
+	for _, c := range cmd.Commands {
+		out += fishCommand(prog, usingFn, append(append([]string{}, path...), c.Name), c)
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func scriptTree() *ScriptCommand {
+	return &ScriptCommand{
+		Name:     "tool",
+		Bundling: true,
+		Options: []ScriptOption{
+			{Aliases: []string{"help", "?"}, Description: "Show help"},
+			{Aliases: []string{"debug", "d"}, Description: "Enable [debug] output:\nmultiline"},
+			{Aliases: []string{"color"}, Description: "Use 'color'", HasArg: true, ArgName: "when", Values: []string{"never", "auto", "always"}},
+			{Aliases: []string{"config", "c"}, HasArg: true, ArgName: "file", Files: true},
+			{Aliases: []string{"tag"}, Description: "Tags", HasArg: true, ArgName: "string", Repeatable: true},
+			{Aliases: []string{"level", "l"}, Description: "Log level", HasArg: true, OptionalArg: true, ArgName: "string"},
+			{Aliases: []string{"output-dir"}, HasArg: true, ArgName: "dir", Dirs: true},
+			{Aliases: []string{"values"}, HasArg: true, ArgName: "file", Files: true, Patterns: []string{"*.yaml"}},
+			{Aliases: []string{"data"}, HasArg: true, ArgName: "file", Files: true, Patterns: []string{"*.json", "*.csv"}},
+		},
+		Commands: []*ScriptCommand{
+			{
+				Name:        "show",
+				Description: "Show a resource",
+				Custom:      []string{"users", "groups"},
+				Options: []ScriptOption{
+					{Aliases: []string{"help", "?"}, Description: "Show help"},
+				},
+			},
+			{
+				Name:        "log",
+				Description: "Log files",
+				Files:       true,
+				Commands: []*ScriptCommand{
+					{Name: "tail-f", Description: "Follow: the log", Files: true, Patterns: []string{"*.log"}},
+					{Name: "rotate", Dirs: true},
+				},
+			},
+		},
+	}
+}
+
+func firstDiff(got, expected string) string {
+	same := ""
+	for i, gc := range got {
+		if len([]rune(expected)) <= i {
+			return fmt.Sprintf("Index: %d | diff: got '%s' - exp '%s'\n", len(expected), got, expected)
+		}
+		if gc != []rune(expected)[i] {
+			return fmt.Sprintf("Index: %d | diff: got '%c' - exp '%c'\nsame '%s'\n", i, gc, []rune(expected)[i], same)
+		}
+		same += string(gc)
+	}
+	if len(expected) > len(got) {
+		return fmt.Sprintf("Index: %d | diff: got '%s' - exp '%s'\n", len(got), got, expected)
+	}
+	return ""
+}
+
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		err := ioutil.WriteFile(path, []byte(got), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(expected) {
+		t.Errorf("Unexpected script for %s:\n%s", name, firstDiff(got, string(expected)))
+	}
+}
+
+func TestZshScript(t *testing.T) {
+	checkGolden(t, "zsh.golden", ZshScript("tool", scriptTree()))
+	checkGolden(t, "zsh_empty.golden", ZshScript("my-tool", &ScriptCommand{Name: "my-tool"}))
+}
+
+func TestFishScript(t *testing.T) {
+	checkGolden(t, "fish.golden", FishScript("tool", scriptTree()))
+	checkGolden(t, "fish_empty.golden", FishScript("my-tool", &ScriptCommand{Name: "my-tool"}))
+}
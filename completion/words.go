@@ -0,0 +1,107 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SplitWords - Splits a command line into words following the shell quoting rules:
+// single quotes, double quotes and backslash escapes.
+//
+// The last word can be partial, with an open quote, and its quote character is returned (0 when unquoted).
+// When the line ends in unquoted whitespace an empty word is added at the end, it is the word being completed.
+func SplitWords(line string) ([]string, rune) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+				continue
+			}
+			word.WriteRune(r)
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+				continue
+			}
+			// Inside double quotes a backslash only escapes: $ ` " \ and newline
+			if r == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+				i++
+				r = runes[i]
+			}
+			word.WriteRune(r)
+		case r == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+		case r == '\'' || r == '"':
+			inWord = true
+			quote = r
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			inWord = true
+			word.WriteRune(r)
+		}
+	}
+	if inWord || quote != 0 {
+		words = append(words, word.String())
+	} else if len(runes) > 0 {
+		words = append(words, "")
+	}
+	return words, quote
+}
+
+// shellSpecialChars - Characters that need to be escaped in an unquoted word.
+const shellSpecialChars = " \t\n'\"\\$`&|;()<>*?[]{}!#~"
+
+// EscapeWord - Escapes the completion so the shell inserts it as a single word.
+// quote is the quote character the word being completed started with, as returned by SplitWords.
+//
+// Quoted completions are closed unless they end in a slash, a directory, so the user can keep completing.
+// The trailing space added to a single directory result, to prevent the shell from adding a space after it, is kept unescaped.
+func EscapeWord(s string, quote rune) string {
+	suffix := ""
+	if strings.HasSuffix(s, "/ ") {
+		s = strings.TrimSuffix(s, " ")
+		suffix = " "
+	}
+	closing := ""
+	if !strings.HasSuffix(s, "/") {
+		closing = string(quote)
+	}
+	switch quote {
+	case '\'':
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + closing + suffix
+	case '"':
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s) + closing + suffix
+	}
+	var out strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(shellSpecialChars, r) {
+			out.WriteRune('\\')
+		}
+		out.WriteRune(r)
+	}
+	return out.String() + suffix
+}
@@ -0,0 +1,114 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completion
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+		quote    rune
+	}{
+		{"empty", "", []string{}, 0},
+		{"words", "prog  log -d", []string{"prog", "log", "-d"}, 0},
+		{"trailing space", "prog log ", []string{"prog", "log", ""}, 0},
+		{"escaped space", `prog my\ fi`, []string{"prog", "my fi"}, 0},
+		{"escaped trailing space", `prog my\ `, []string{"prog", "my "}, 0},
+		{"single quotes", `prog 'my "file' x`, []string{"prog", `my "file`, "x"}, 0},
+		{"double quotes", `prog "a \"b\" \c $" x`, []string{"prog", `a "b" \c $`, "x"}, 0},
+		{"concatenated", `prog --file="my file"s`, []string{"prog", "--file=my files"}, 0},
+		{"empty quotes", `prog '' x`, []string{"prog", "", "x"}, 0},
+		{"open single quote", `prog 'my fi`, []string{"prog", "my fi"}, '\''},
+		{"open double quote", `prog "my fi`, []string{"prog", "my fi"}, '"'},
+		{"open quote no text", `prog "`, []string{"prog", ""}, '"'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, quote := SplitWords(tt.line)
+			if !reflect.DeepEqual(got, tt.expected) || quote != tt.quote {
+				t.Errorf("got: %q %q, expected: %q %q", got, quote, tt.expected, tt.quote)
+			}
+		})
+	}
+}
+
+func TestEscapeWord(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		quote    rune
+		expected string
+	}{
+		{"plain", "--help", 0, "--help"},
+		{"space", "my file", 0, `my\ file`},
+		{"special", `it's $a (b)`, 0, `it\'s\ \$a\ \(b\)`},
+		{"dir", "my dir/", 0, `my\ dir/`},
+		{"dir space suffix", "my dir/ ", 0, `my\ dir/ `},
+		{"single", "it's", '\'', `'it'\''s'`},
+		{"single dir", "my dir/", '\'', `'my dir/`},
+		{"single dir space suffix", "my dir/ ", '\'', `'my dir/ `},
+		{"double", `a "$b"`, '"', `"a \"\$b\""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EscapeWord(tt.s, tt.quote)
+			if got != tt.expected {
+				t.Errorf("got: %s, expected: %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCompLineCompleteQuoted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "completion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"my file", "my fish", "it's"} {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte{}, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootNode := NewNode("executable", Root, nil)
+	rootNode.AddChild(NewNode("options", OptionsNode, []string{"--help"}))
+	rootNode.AddChild(NewNode(dir, FileListNode, nil))
+
+	tests := []struct {
+		name     string
+		compLine string
+		expected []string
+	}{
+		{"unquoted", "executable my", []string{`my\ file`, `my\ fish`}},
+		{"escaped", `executable my\ fi`, []string{`my\ file`, `my\ fish`}},
+		{"escaped full", `executable my\ fil`, []string{`my\ file`}},
+		{"single quote", `executable 'my fil`, []string{`'my file'`}},
+		{"double quote", `executable "my fil`, []string{`"my file"`}},
+		{"quote in name", `executable it`, []string{`it\'s`}},
+		{"after file with space", `executable my\ file --h`, []string{"--help"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rootNode.CompLineComplete(false, tt.compLine)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got: %q, expected: %q", got, tt.expected)
+			}
+		})
+	}
+}
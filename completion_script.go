@@ -0,0 +1,121 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zhizh/go-getoptions/completion"
+	"github.com/zhizh/go-getoptions/option"
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// CompletionScript - Returns a completion script for the given shell.
+// Supported shells: bash, zsh and fish.
+//
+// The bash script registers the program itself as the completion command, completions are resolved at runtime.
+// The script includes the option and command descriptions.
+// For example, to install it for zsh:
+//
+//     script, err := opt.CompletionScript("zsh")
+//     // Save script as _<program> in a directory in $fpath
+//
+// For fish, save the script as ~/.config/fish/completions/<program>.fish.
+func (gopt *GetOpt) CompletionScript(shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return fmt.Sprintf("complete -o default -C %[1]s %[1]s\n", gopt.name), nil
+	case "zsh":
+		return completion.ZshScript(gopt.name, gopt.scriptCommand()), nil
+	case "fish":
+		return completion.FishScript(gopt.name, gopt.scriptCommand()), nil
+	}
+	return "", fmt.Errorf(text.ErrorUnsupportedShell, shell)
+}
+
+// scriptCommand - Returns the completion script description of the command and its children.
+func (gopt *GetOpt) scriptCommand() *completion.ScriptCommand {
+	cmd := &completion.ScriptCommand{
+		Name:        gopt.name,
+		Description: gopt.description,
+		Bundling:    gopt.mode == Bundling,
+	}
+
+	// Options are passed to the children at Parse time so include the ones defined in the parents.
+	seen := map[string]bool{}
+	for g := gopt; g != nil; g = g.parent {
+		for _, opt := range g.obj {
+			if seen[opt.Name] || opt.IsHidden {
+				continue
+			}
+			seen[opt.Name] = true
+			cmd.Options = append(cmd.Options, scriptOption(opt))
+		}
+	}
+
+	for _, node := range gopt.completion.GetChildrenByKind(completion.CustomNode) {
+		cmd.Custom = append(cmd.Custom, node.GetEntries()...)
+	}
+	for _, node := range gopt.completion.GetChildrenByKind(completion.FileListNode) {
+		cmd.Files = true
+		cmd.Patterns = append(cmd.Patterns, node.Patterns...)
+	}
+	if len(gopt.completion.GetChildrenByKind(completion.DirListNode)) > 0 {
+		cmd.Dirs = true
+	}
+
+	for _, command := range gopt.commands {
+		if !command.hidden {
+			cmd.Commands = append(cmd.Commands, command.scriptCommand())
+		}
+	}
+	return cmd
+}
+
+// scriptOption - Returns the completion script description of the option.
+func scriptOption(opt *option.Option) completion.ScriptOption {
+	o := completion.ScriptOption{
+		Aliases:     opt.Aliases,
+		Description: opt.Description,
+		HasArg:      opt.OptType != option.BoolType,
+		OptionalArg: opt.IsOptional,
+		ArgName:     opt.HelpArgName,
+		Values:      opt.ValidValues,
+		Files:       opt.FileCompletion,
+		Patterns:    opt.FilePatterns,
+		Dirs:        opt.DirCompletion,
+	}
+	switch opt.OptType {
+	case option.StringRepeatType, option.IntRepeatType, option.StringMapType,
+		option.Int64RepeatType, option.UintRepeatType, option.Uint64RepeatType, option.DurationRepeatType:
+		o.Repeatable = true
+	}
+	return o
+}
+
+// CompletionCommand - Adds a completion command that prints the completion script for the shell given as argument: bash, zsh or fish.
+// The command help documents how to install the scripts.
+//
+// The scripts are generated for the whole program when the command is called, so it can be defined before other commands.
+func (gopt *GetOpt) CompletionCommand() *GetOpt {
+	root := gopt.root()
+	// TODO: "completion" is hardcoded
+	opt := gopt.NewCommand("completion", fmt.Sprintf(text.HelpCompletionCommandDescription, root.name))
+	shell := opt.StringArg("shell", opt.Required(), opt.ValidValues("bash", "zsh", "fish"))
+	opt.SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error {
+		script, err := root.CompletionScript(*shell)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(completionWriter, script)
+		return nil
+	})
+	return opt
+}
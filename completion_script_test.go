@@ -0,0 +1,151 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zhizh/go-getoptions/text"
+)
+
+func TestCompletionScript(t *testing.T) {
+	opt := New()
+	opt.name = "tool"
+	opt.Bool("help", false, opt.Alias("?"))
+	opt.Bool("debug", false, opt.Description("Enable debug output"))
+	opt.String("color", "auto", opt.ValidValues("never", "auto", "always"), opt.ArgName("when"))
+	opt.StringSlice("tag", 1, 1)
+	opt.StringOptional("level", "info")
+	show := opt.NewCommand("show", "Show a resource")
+	show.String("format", "", opt.Description("Output format"))
+	show.NewCommand("users", "List users")
+	opt.NewCommand("log", "Log files").StringArg("file", opt.CompleteFiles())
+
+	script, err := opt.CompletionScript("zsh")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expected := range []string{
+		"\t\t'--debug[Enable debug output]' \\\n",
+		"\t\t'--color=:when:(never auto always)' \\\n",
+		"\t\t'*--tag=:string: ' \\\n",
+		"\t\t'--level=::string: ' \\\n",
+		"\t_arguments -C \\\n",
+		"\t\t\t'show:Show a resource'\n",
+		"\n_tool_show() {\n",
+		// Parent options are included in the commands
+		"\t\t'--format=[Output format]:string: ' \\\n\t\t'(--help -?)--help' \\\n",
+		"\t\t'*--tag=:string: ' \\\n\t\t'1: :->command' \\\n",
+		"\t\t\t'users:List users'\n",
+		"\t\t'*: :_files'\n",
+		"\tcompdef _tool tool\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Missing '%s' in script:\n%s", expected, script)
+		}
+	}
+
+	opt.SetMode(Bundling)
+	script, err = opt.CompletionScript("zsh")
+	if err != nil || !strings.Contains(script, "\ttypeset -A opt_args\n\t_arguments -C -s \\\n") || strings.Contains(script, "_tool_log() {\n\t_arguments -C -s") {
+		t.Errorf("Unexpected bundling in script:\n%s", script)
+	}
+
+	script, err = opt.CompletionScript("fish")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expected := range []string{
+		"complete -c tool -n '__fish_tool_using_command' -f -a 'show' -d 'Show a resource'\n",
+		"complete -c tool -n '__fish_tool_using_command' -l 'color' -x -a 'never auto always'\n",
+		"complete -c tool -n '__fish_tool_using_command' -l 'help' -s '?'\n",
+		// Parent options are included in the commands
+		"complete -c tool -n '__fish_tool_using_command show users' -l 'debug' -d 'Enable debug output'\n",
+		"complete -c tool -n '__fish_tool_using_command show' -l 'format' -d 'Output format' -x\n",
+		"complete -c tool -n '__fish_tool_using_command show' -f -a 'users' -d 'List users'\n",
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Missing '%s' in script:\n%s", expected, script)
+		}
+	}
+	if strings.Contains(script, "complete -c tool -n '__fish_tool_using_command log' -f\n") {
+		t.Errorf("Unexpected file completion disabled for log:\n%s", script)
+	}
+
+	_, err = opt.CompletionScript("tcsh")
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorUnsupportedShell, "tcsh") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	defer func() { completionWriter = os.Stdout }()
+	setup := func() (*GetOpt, *bytes.Buffer) {
+		buf := new(bytes.Buffer)
+		completionWriter = buf
+		opt := New()
+		opt.name = "tool"
+		opt.SetRequireOrder()
+		opt.SetUnknownMode(Pass)
+		opt.Bool("debug", false)
+		opt.CompletionCommand()
+		opt.NewCommand("log", "Log files").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.HelpCommand("")
+		return opt, buf
+	}
+
+	for _, tt := range []struct {
+		shell    string
+		expected string
+	}{
+		{"bash", "complete -o default -C tool tool\n"},
+		{"zsh", "\t\t\t'log:Log files'\n"},
+		{"fish", "complete -c tool -n '__fish_tool_using_command completion' -f -a 'bash zsh fish'\n"},
+	} {
+		t.Run(tt.shell, func(t *testing.T) {
+			opt, buf := setup()
+			remaining, err := opt.Parse([]string{"completion", tt.shell})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			err = opt.Dispatch(context.Background(), "help", remaining)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Missing '%s' in script:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		opt, _ := setup()
+		err := opt.Dispatch(context.Background(), "help", []string{"completion"})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingRequiredArgument, "shell") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", []string{"completion", "tcsh"})
+		if err == nil || !strings.Contains(err.Error(), "tcsh") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt, _ := setup()
+		helpTxt := opt.commands["completion"].Help(HelpName)
+		if !strings.Contains(helpTxt, "source <(tool completion bash)") || !strings.Contains(helpTxt, "~/.config/fish/completions/tool.fish") {
+			t.Errorf("Unexpected help:\n%s", helpTxt)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+/*
+Package completiontest - provides helpers to unit test the completions of a go-getoptions program.
+
+The completions are computed in-process, without setting COMP_LINE and without exiting:
+
+  func TestCompletion(t *testing.T) {
+      opt := setupOptions()
+      got := completiontest.Complete(opt, "myprog --pro", -1)
+      if !reflect.DeepEqual(got, []string{"--profile"}) {
+          t.Errorf("Unexpected completions: %v", got)
+      }
+  }
+*/
+package completiontest
+
+import (
+	"github.com/zhizh/go-getoptions"
+)
+
+// Complete - Returns the completions for the line up to the cursor position point, as the shell would get them.
+// A negative point completes the whole line.
+// The line starts with the program name, for example: "myprog log --pro".
+func Complete(opt *getoptions.GetOpt, line string, point int) []string {
+	return opt.Complete(line, point)
+}
+
+// CompleteLine - Returns the completions for the whole line, the cursor is at the end of the line.
+func CompleteLine(opt *getoptions.GetOpt, line string) []string {
+	return opt.Complete(line, -1)
+}
@@ -0,0 +1,57 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package completiontest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zhizh/go-getoptions"
+)
+
+func setup() *getoptions.GetOpt {
+	opt := getoptions.New()
+	opt.Bool("debug", false, opt.Alias("d"))
+	opt.String("profile", "", opt.ValidValues("default", "prod"))
+	log := opt.NewCommand("log", "")
+	log.Bool("follow", false)
+	opt.NewCommand("show", "").CustomCompletion([]string{"users", "groups"})
+	return opt
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		point    int
+		expected []string
+	}{
+		{"commands", "prog ", -1, []string{"log", "show"}},
+		{"options", "prog --", -1, []string{"--debug", "--profile"}},
+		{"option value", "prog --profile=p", -1, []string{"prod"}},
+		{"command options", "prog log --f", -1, []string{"--follow"}},
+		{"custom", "prog show g", -1, []string{"groups"}},
+		{"point", "prog l --debug", 6, []string{"log"}},
+		{"point end", "prog --pro", 10, []string{"--profile"}},
+		{"point past end", "prog --pro", 20, []string{"--profile"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Complete(setup(), tt.line, tt.point)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got: %v, expected: %v", got, tt.expected)
+			}
+		})
+	}
+
+	got := CompleteLine(setup(), "prog sh")
+	if !reflect.DeepEqual(got, []string{"show"}) {
+		t.Errorf("got: %v, expected: %v", got, []string{"show"})
+	}
+}
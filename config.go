@@ -0,0 +1,313 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zhizh/go-getoptions/option"
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// ConfigFile - Read option values from the given config file.
+// Precedence higher to lower: CLI option, environment variable, config file, option default.
+//
+// Files with the `.json` extension are read as JSON objects.
+// Any other file is read as a simple INI/`.env` style file:
+//
+//     # Comments start with '#' or ';'
+//     host = example.com
+//     export PORT=8080 # Trailing comments need a space before them
+//     list = a
+//     list = b
+//
+//     [log]
+//     since = "1 week"
+//
+// Options are looked up by their name, use `opt.ConfigKey` to use a different key.
+// Options defined in commands are first looked up under a section (INI) or object (JSON)
+// named after the command and then at the top level.
+// For example, the `since` option of the `log` command is first looked up as `log.since` and then as `since`.
+//
+// Slice options take a JSON array or a repeated INI key.
+// Map options take a JSON object or a repeated INI key with `key=value` values.
+//
+// When an option is set from the config file, opt.Called(name) is set to true
+// and opt.CalledAs(name) is set to the file and the key used, for example: `config.json:port`.
+// In other words, when an option is required (opt.Required is set) the config file satisfies that requirement.
+//
+// A missing config file is not an error.
+//
+// Commands inherit the config file of their parent.
+// When parsing stops at a command (see SetRequireOrder), the file is read when the command parses its arguments.
+func (gopt *GetOpt) ConfigFile(path string) *GetOpt {
+	gopt.configFile = path
+	return gopt
+}
+
+// ConfigKey - Key used to look up the option value in the config file.
+// Defaults to the option name.
+func (gopt *GetOpt) ConfigKey(key string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetConfigKey(key)
+	}
+}
+
+// configFilePath - Returns the config file path for the command or the closest parent that defines one.
+func (gopt *GetOpt) configFilePath() string {
+	if gopt.configFile != "" || gopt.parent == nil {
+		return gopt.configFile
+	}
+	return gopt.parent.configFilePath()
+}
+
+// configPrefix - Returns the key prefix for options of the command, for example: "log.sublog.".
+func (gopt *GetOpt) configPrefix() string {
+	if !gopt.isCommand {
+		return ""
+	}
+	return gopt.parent.configPrefix() + gopt.name + "."
+}
+
+// applyConfigFile - Saves the values from the config file into the options that haven't been called.
+func (gopt *GetOpt) applyConfigFile() error {
+	path := gopt.configFilePath()
+	if path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			Debug.Printf("config file %s not found\n", path)
+			return nil
+		}
+		return fmt.Errorf(text.ErrorConfigFile, path, err)
+	}
+	var config map[string]interface{}
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		config, err = parseJSONConfig(data)
+	} else {
+		config, err = parseINIConfig(data)
+	}
+	if err != nil {
+		return fmt.Errorf(text.ErrorConfigFile, path, err)
+	}
+
+	// Sort for consistent error reporting
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt := gopt.obj[name]
+		if opt.Called {
+			continue
+		}
+		key := opt.Name
+		if opt.ConfigKey != "" {
+			key = opt.ConfigKey
+		}
+		value, ok := lookupConfig(config, gopt.configPrefix()+key)
+		if !ok {
+			value, ok = lookupConfig(config, key)
+			if !ok {
+				continue
+			}
+		} else {
+			key = gopt.configPrefix() + key
+		}
+		Debug.Printf("config file %s: %s = %v\n", path, key, value)
+		err := saveConfigValue(opt, path+":"+key, value)
+		if err != nil {
+			return err
+		}
+		if opt.IsDeprecated {
+			gopt.warnDeprecated(text.WarningDeprecatedOption, opt.UsedAlias, opt.DeprecatedMsg)
+		}
+	}
+	return nil
+}
+
+// lookupConfig - Finds the value for the dot separated key.
+func lookupConfig(config map[string]interface{}, key string) (interface{}, bool) {
+	parts := strings.Split(key, ".")
+	var current interface{} = config
+	for _, part := range parts {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// saveConfigValue - Saves the value read from the config file into the option.
+func saveConfigValue(opt *option.Option, source string, value interface{}) error {
+	opt.SetCalled(source)
+	switch opt.OptType {
+	case option.StringRepeatType, option.IntRepeatType, option.Int64RepeatType, option.UintRepeatType,
+		option.Uint64RepeatType, option.DurationRepeatType:
+		for _, e := range configValueToList(value) {
+			err := opt.Save(e)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case option.StringMapType:
+		list := []string{}
+		if m, ok := value.(map[string]interface{}); ok {
+			keys := []string{}
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				for _, v := range configValueToList(m[k]) {
+					list = append(list, k+"="+v)
+				}
+			}
+		} else {
+			list = configValueToList(value)
+		}
+		for _, e := range list {
+			err := opt.Save(e)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	list := configValueToList(value)
+	if len(list) == 0 {
+		return nil
+	}
+	// Single value options use the last value
+	v := list[len(list)-1]
+	if opt.OptType == option.BoolType {
+		v = strings.ToLower(v)
+		if v != "true" && v != "false" {
+			return &ConversionError{Name: opt.Name, Alias: source, Argument: list[len(list)-1], Type: "bool"}
+		}
+	}
+	return opt.Save(v)
+}
+
+// configValueToList - Converts a value read from the config file into a list of string arguments.
+func configValueToList(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		list := []string{}
+		for _, e := range v {
+			list = append(list, configValueToList(e)...)
+		}
+		return list
+	case string:
+		return []string{v}
+	case json.Number:
+		return []string{v.String()}
+	case bool:
+		return []string{fmt.Sprintf("%t", v)}
+	case nil:
+		return []string{}
+	default:
+		return []string{fmt.Sprintf("%v", v)}
+	}
+}
+
+func parseJSONConfig(data []byte) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	err := d.Decode(&config)
+	return config, err
+}
+
+// parseINIConfig - Parses a simple INI/.env style file.
+// Sections become nested maps and repeated keys become lists.
+func parseINIConfig(data []byte) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	section := config
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = config
+			for _, name := range strings.Split(strings.TrimSpace(line[1:len(line)-1]), ".") {
+				child, ok := section[name].(map[string]interface{})
+				if !ok {
+					child = map[string]interface{}{}
+					section[name] = child
+				}
+				section = child
+			}
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		keyValue := strings.SplitN(line, "=", 2)
+		if len(keyValue) < 2 {
+			return config, fmt.Errorf("line %d: should be of type 'key=value'", n)
+		}
+		key := strings.TrimSpace(keyValue[0])
+		value := unquote(stripComment(strings.TrimSpace(keyValue[1])))
+		switch v := section[key].(type) {
+		case nil:
+			section[key] = value
+		case []interface{}:
+			section[key] = append(v, value)
+		default:
+			section[key] = []interface{}{v, value}
+		}
+	}
+	return config, scanner.Err()
+}
+
+// stripComment - Removes a trailing comment, a '#' or ';' preceded by a space outside of quotes.
+func stripComment(s string) string {
+	var quote rune
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case (c == '#' || c == ';') && i > 0 && (s[i-1] == ' ' || s[i-1] == '\t'):
+			return strings.TrimSpace(s[:i])
+		}
+	}
+	return s
+}
+
+// unquote - Removes matching surrounding single or double quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,268 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+package getoptions
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// tempDir - Returns a temporary directory removed at the end of the test, t.TempDir requires Go 1.15.
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "go-getoptions")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(tempDir(t), name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return path
+}
+
+func TestConfigFile(t *testing.T) {
+	jsonConfig := `{
+	"debug": true,
+	"host": "example.com",
+	"port": 8080,
+	"timeout": "5s",
+	"list": ["a", "b"],
+	"ids": [1, 2],
+	"labels": {"env": "prod", "team": "core"},
+	"user": "config-user",
+	"log": {"since": "1 week"}
+}`
+	iniConfig := `# comment
+; comment
+debug = true
+host = "example.com"
+export port=8080
+timeout = 5s
+list = a
+list = b
+ids = 1
+ids = 2
+labels = env=prod
+labels = team=core
+user = 'config-user'
+
+[log]
+since = 1 week
+`
+	for _, tt := range []struct {
+		name    string
+		content string
+	}{
+		{"config.json", jsonConfig},
+		{"config.ini", iniConfig},
+		{".env", iniConfig},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.name, tt.content)
+			opt := New()
+			opt.ConfigFile(path)
+			debug := opt.Bool("debug", false)
+			host := opt.String("host", "")
+			port := opt.Int("port", 0, opt.Required())
+			timeout := opt.Duration("timeout", 0)
+			list := opt.StringSlice("list", 1, 99)
+			ids := opt.IntSlice("ids", 1, 99)
+			labels := opt.StringMap("labels", 1, 99)
+			username := opt.String("username", "", opt.ConfigKey("user"))
+			missing := opt.String("missing", "default")
+			_, err := opt.Parse([]string{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !*debug || *host != "example.com" || *port != 8080 || *timeout != 5*time.Second ||
+				*username != "config-user" || *missing != "default" {
+				t.Errorf("Unexpected values: %v, %v, %v, %v, %v, %v", *debug, *host, *port, *timeout, *username, *missing)
+			}
+			if !reflect.DeepEqual(*list, []string{"a", "b"}) || !reflect.DeepEqual(*ids, []int{1, 2}) ||
+				!reflect.DeepEqual(labels, map[string]string{"env": "prod", "team": "core"}) {
+				t.Errorf("Unexpected values: %v, %v, %v", *list, *ids, labels)
+			}
+			if !opt.Called("port") || opt.CalledAs("port") != path+":port" {
+				t.Errorf("Unexpected called as: %v, %s", opt.Called("port"), opt.CalledAs("port"))
+			}
+			if !opt.Called("username") || opt.CalledAs("username") != path+":user" {
+				t.Errorf("Unexpected called as: %v, %s", opt.Called("username"), opt.CalledAs("username"))
+			}
+			if opt.Called("missing") {
+				t.Errorf("missing shouldn't be called")
+			}
+		})
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		path := writeConfig(t, "config.json", jsonConfig)
+		os.Setenv("_get_opt_env_host", "env.com")
+		defer os.Unsetenv("_get_opt_env_host")
+		opt := New()
+		opt.ConfigFile(path)
+		host := opt.String("host", "", opt.GetEnv("_get_opt_env_host"))
+		port := opt.Int("port", 0)
+		list := opt.StringSlice("list", 1, 99)
+		labels := opt.StringMap("labels", 1, 99)
+		_, err := opt.Parse([]string{"--port", "9090", "--list", "c", "--labels", "env=dev"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if *host != "env.com" || *port != 9090 {
+			t.Errorf("Unexpected values: %v, %v", *host, *port)
+		}
+		// Values from the command line replace the values from the config file
+		if !reflect.DeepEqual(*list, []string{"c"}) || !reflect.DeepEqual(labels, map[string]string{"env": "dev"}) {
+			t.Errorf("Unexpected values: %v, %v", *list, labels)
+		}
+	})
+
+	t.Run("trailing comments", func(t *testing.T) {
+		path := writeConfig(t, "config.ini", `port = 8080 # dev
+host = "example.com" ; quoted
+password = "se#cret ;x" # the quotes keep the '#' and ';'
+channel = #general
+`)
+		opt := New()
+		opt.ConfigFile(path)
+		port := opt.Int("port", 0)
+		host := opt.String("host", "")
+		password := opt.String("password", "")
+		channel := opt.String("channel", "")
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if *port != 8080 || *host != "example.com" || *password != "se#cret ;x" || *channel != "#general" {
+			t.Errorf("Unexpected values: %v, %v, %v, %v", *port, *host, *password, *channel)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		opt := New()
+		opt.ConfigFile(filepath.Join(tempDir(t), "missing.json"))
+		host := opt.String("host", "default")
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if *host != "default" {
+			t.Errorf("Unexpected value: %v", *host)
+		}
+	})
+
+	t.Run("missing required", func(t *testing.T) {
+		path := writeConfig(t, "config.json", `{"host": "example.com"}`)
+		opt := New()
+		opt.ConfigFile(path)
+		opt.Int("port", 0, opt.Required())
+		_, err := opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingRequiredOption, "port") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		path := writeConfig(t, "config.json", `{"port": "x"}`)
+		opt := New()
+		opt.ConfigFile(path)
+		opt.Int("port", 0)
+		_, err := opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToInt, path+":port", "x") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		path = writeConfig(t, "config.ini", "debug = maybe\n")
+		opt = New()
+		opt.ConfigFile(path)
+		opt.Bool("debug", false)
+		_, err = opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToBool, path+":debug", "maybe") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		path = writeConfig(t, "config.ini", "debug\n")
+		opt = New()
+		opt.ConfigFile(path)
+		opt.Bool("debug", false)
+		_, err = opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConfigFile, path, "line 1: should be of type 'key=value'") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		path = writeConfig(t, "config.json", `{"debug"`)
+		opt = New()
+		opt.ConfigFile(path)
+		opt.Bool("debug", false)
+		_, err = opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConfigFile, path, "unexpected EOF") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("command", func(t *testing.T) {
+		path := writeConfig(t, "config.json", jsonConfig)
+		var since, host string
+		opt := New()
+		opt.ConfigFile(path)
+		opt.StringVar(&host, "host", "")
+		logCmd := opt.NewCommand("log", "")
+		logCmd.StringVar(&since, "since", "")
+		logCmd.SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		remaining, err := opt.Parse([]string{"log"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", remaining)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if since != "1 week" || host != "example.com" {
+			t.Errorf("Unexpected values: %v, %v", since, host)
+		}
+	})
+
+	t.Run("precedence across commands", func(t *testing.T) {
+		path := writeConfig(t, "config.json", jsonConfig)
+		for _, args := range [][]string{{"--list", "c", "log"}, {"log", "--list", "c"}} {
+			var list []string
+			opt := New()
+			opt.ConfigFile(path)
+			opt.SetRequireOrder()
+			opt.StringSliceVar(&list, "list", 1, 1)
+			opt.NewCommand("log", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+			remaining, err := opt.Parse(args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			err = opt.Dispatch(context.Background(), "help", remaining)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(list, []string{"c"}) {
+				t.Errorf("%v: unexpected value: %v", args, list)
+			}
+		}
+	})
+}
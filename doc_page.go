@@ -0,0 +1,49 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import "github.com/zhizh/go-getoptions/help"
+
+// DocFormat - Markup language of the reference documentation.
+type DocFormat int
+
+// Reference documentation formats
+const (
+	Markdown DocFormat = iota
+	AsciiDoc
+)
+
+func (format DocFormat) helpFormat() help.DocFormat {
+	if format == AsciiDoc {
+		return help.AsciiDocFormat
+	}
+	return help.MarkdownFormat
+}
+
+// Doc - Returns the Markdown or AsciiDoc reference document for the program or command.
+//
+// The document has the same SYNOPSIS, COMMANDS, ARGUMENTS and OPTIONS sections as the help output.
+// Options are listed in a table with their aliases, type, default, environment variable, whether they are required and description.
+// The document links to the parent and child command documents.
+func (gopt *GetOpt) Doc(format DocFormat) string {
+	gopt.root().passOptionsToChildren()
+	return help.Doc(gopt.page(0), format.helpFormat())
+}
+
+// Docs - Returns the Markdown or AsciiDoc reference documents for the program and all its commands.
+// The documents are indexed by file name, for example: "mygit.md" and "mygit-log.md".
+func (gopt *GetOpt) Docs(format DocFormat) map[string]string {
+	gopt.root().passOptionsToChildren()
+	docs := map[string]string{}
+	gopt.walkCommands(func(opt *GetOpt) {
+		name := getCommandName(opt)
+		docs[help.DocFile(name, format.helpFormat())] = help.Doc(opt.page(0), format.helpFormat())
+	})
+	return docs
+}
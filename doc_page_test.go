@@ -0,0 +1,40 @@
+package getoptions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDocs(t *testing.T) {
+	opt := New()
+	opt.name = "tool"
+	opt.Bool("debug", false)
+	log := opt.NewCommand("log", "Show logs")
+	log.NewCommand("tail", "Follow logs")
+
+	for _, format := range []DocFormat{Markdown, AsciiDoc} {
+		ext := ".md"
+		if format == AsciiDoc {
+			ext = ".adoc"
+		}
+		docs := opt.Docs(format)
+		names := []string{}
+		for name := range docs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		expected := fmt.Sprintf("tool-log-tail%[1]s tool-log%[1]s tool%[1]s", ext)
+		if strings.Join(names, " ") != expected {
+			t.Errorf("Unexpected docs: %v, expected %s\n", names, expected)
+		}
+		if docs["tool-log"+ext] != log.Doc(format) {
+			t.Errorf("Unexpected doc:\n%s", firstDiff(docs["tool-log"+ext], log.Doc(format)))
+		}
+		// Inherited options are documented in the commands
+		if !strings.Contains(docs["tool-log-tail"+ext], "--debug") {
+			t.Errorf("Missing inherited option:\n%s", docs["tool-log-tail"+ext])
+		}
+	}
+}
@@ -35,7 +35,15 @@ Features
 
 • Simple synopsis and option list automated help.
 
-• Boolean, String, Int and Float64 type options.
+• Hidden and deprecated options and commands.
+
+• Man page and Markdown/AsciiDoc reference documentation generation for the program and all its commands.
+
+• Machine readable JSON schema export of the options and commands and breaking change detection between versions.
+
+• Boolean, String, Int, Int64, Uint, Uint64, Float64 and Duration type options.
+
+• User defined type options through the `Value` interface.
 
 • Negatable Boolean options.
 For example: `--verbose`, `--no-verbose` or `--noverbose`.
@@ -83,6 +91,14 @@ For example, you can use `v` to define `verbose` and `V` to define `Version`.
 
 • Support indicating if an option is required and allows overriding default error message.
 
+• Set options by reading Environment Variables or a JSON or INI/`.env` style config file.
+
+• Positional arguments with types, arity, help and completion.
+
+• Zsh and fish completion script generation with option and command descriptions and a built in `completion` command.
+
+• Define options from struct fields with `getoptions` struct tags.
+
 • Errors exposed as public variables to allow overriding them for internationalization.
 
 • Supports subcommands (stop parsing arguments when non option is passed).
@@ -0,0 +1,134 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zhizh/go-getoptions/option"
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// The errors returned by Parse can be inspected with errors.As, for example:
+//
+//     _, err := opt.Parse(os.Args[1:])
+//     var unknown *getoptions.UnknownOptionError
+//     if errors.As(err, &unknown) {
+//         fmt.Println(unknown.Name)
+//     }
+//
+// Their messages are formatted with the text package variables so they can be translated.
+
+// ConversionError - Error returned when an option argument can't be converted to the option type.
+type ConversionError = option.ConversionError
+
+// MissingRequiredError - Error returned when a required option or positional argument wasn't provided.
+type MissingRequiredError = option.MissingRequiredError
+
+// InvalidValueError - Error returned when an option argument is not one of the option valid values.
+type InvalidValueError = option.InvalidValueError
+
+// ValidationError - Error returned when an option value doesn't pass its validation function.
+// The validation function error can be inspected with errors.Is and errors.As.
+type ValidationError = option.ValidationError
+
+// TooManyArgumentsError - Error returned when more positional arguments than the ones defined are passed.
+type TooManyArgumentsError struct {
+	Arguments []string // Unexpected arguments
+}
+
+func (e *TooManyArgumentsError) Error() string {
+	return fmt.Sprintf(text.ErrorTooManyArguments, e.Arguments)
+}
+
+// UnknownOptionError - Error returned when the option is not defined and the unknown mode is Fail.
+type UnknownOptionError struct {
+	Name        string   // Option as passed on the command line without leading dashes
+	Suggestions []string // Similar options, as they are passed on the command line: -o or --option
+}
+
+func (e *UnknownOptionError) Error() string {
+	return withSuggestions(fmt.Sprintf(text.MessageOnUnknown, e.Name), e.Suggestions)
+}
+
+// UnknownCommandError - Error returned by Dispatch when the argument is not a command.
+type UnknownCommandError struct {
+	Name        string   // Argument passed on the command line
+	Suggestions []string // Similar commands
+}
+
+func (e *UnknownCommandError) Error() string {
+	if strings.HasPrefix(e.Name, "-") {
+		return withSuggestions(fmt.Sprintf(text.ErrorNotACommandOrOption, e.Name), e.Suggestions)
+	}
+	return withSuggestions(fmt.Sprintf(text.ErrorNotACommand, e.Name), e.Suggestions)
+}
+
+// withSuggestions - Appends the "did you mean" message to msg when there are suggestions.
+func withSuggestions(msg string, suggestions []string) string {
+	if len(suggestions) == 0 {
+		return msg
+	}
+	return msg + "\n" + fmt.Sprintf(text.MessageDidYouMean, strings.Join(suggestions, ", "))
+}
+
+// MissingArgumentError - Error returned when an option that requires an argument doesn't get one.
+type MissingArgumentError struct {
+	Name     string // Option name
+	Alias    string // Alias used to call the option
+	Argument string // Next argument, that looks like an option, when present
+}
+
+func (e *MissingArgumentError) Error() string {
+	if e.Argument != "" {
+		return fmt.Sprintf(text.ErrorArgumentWithDash, e.Alias)
+	}
+	return fmt.Sprintf(text.ErrorMissingArgument, e.Alias)
+}
+
+// AmbiguousOptionError - Error returned when an abbreviated option matches more than one option or command.
+type AmbiguousOptionError struct {
+	Alias      string   // Alias as passed on the command line
+	Candidates []string // Options and commands matched by the alias
+}
+
+func (e *AmbiguousOptionError) Error() string {
+	return fmt.Sprintf(text.ErrorAmbiguousArgument, e.Alias, e.Candidates)
+}
+
+// errNoMoreArguments - Indicates that there are no more arguments to consume for an option with optional arguments.
+var errNoMoreArguments = errors.New("no more arguments")
+
+// unknownOptionError - Returns an UnknownOptionError with suggestions from the option aliases.
+func (gopt *GetOpt) unknownOptionError(name string) *UnknownOptionError {
+	aliases := []string{}
+	for _, opt := range gopt.obj {
+		if !opt.IsHidden {
+			aliases = append(aliases, opt.Aliases...)
+		}
+	}
+	list := []string{}
+	for _, alias := range suggestions(name, aliases) {
+		list = append(list, option.Dashed(alias))
+	}
+	return &UnknownOptionError{Name: name, Suggestions: list}
+}
+
+// commandSuggestions - Returns the commands with names similar to name.
+func (gopt *GetOpt) commandSuggestions(name string) []string {
+	names := []string{}
+	for n, command := range gopt.commands {
+		if !command.hidden {
+			names = append(names, n)
+		}
+	}
+	return suggestions(name, names)
+}
@@ -0,0 +1,260 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/zhizh/go-getoptions/text"
+)
+
+func TestErrors(t *testing.T) {
+	setup := func() *GetOpt {
+		opt := New()
+		opt.String("string", "", opt.Alias("s"))
+		opt.Int("int", 0, opt.Alias("i"), opt.Required())
+		opt.StringSlice("list", 1, 3)
+		opt.Bool("stop", false)
+		opt.StringArg("file", opt.Required())
+		return opt
+	}
+
+	t.Run("UnknownOptionError", func(t *testing.T) {
+		_, err := setup().Parse([]string{"--unknown"})
+		var e *UnknownOptionError
+		if !errors.As(err, &e) || e.Name != "unknown" {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.MessageOnUnknown, "unknown") {
+			t.Errorf("Unexpected message: %s", err)
+		}
+	})
+
+	t.Run("MissingArgumentError", func(t *testing.T) {
+		_, err := setup().Parse([]string{"-s"})
+		var e *MissingArgumentError
+		if !errors.As(err, &e) || e.Name != "string" || e.Alias != "s" || e.Argument != "" {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorMissingArgument, "s") {
+			t.Errorf("Unexpected message: %s", err)
+		}
+
+		_, err = setup().Parse([]string{"-s", "--stop"})
+		if !errors.As(err, &e) || e.Name != "string" || e.Alias != "s" || e.Argument != "--stop" {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorArgumentWithDash, "s") {
+			t.Errorf("Unexpected message: %s", err)
+		}
+
+		_, err = setup().Parse([]string{"--list"})
+		if !errors.As(err, &e) || e.Name != "list" {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("AmbiguousOptionError", func(t *testing.T) {
+		_, err := setup().Parse([]string{"--st"})
+		var e *AmbiguousOptionError
+		if !errors.As(err, &e) || e.Alias != "st" || !reflect.DeepEqual(e.Candidates, []string{"stop", "string"}) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorAmbiguousArgument, "st", []string{"stop", "string"}) {
+			t.Errorf("Unexpected message: %s", err)
+		}
+	})
+
+	t.Run("ConversionError", func(t *testing.T) {
+		_, err := setup().Parse([]string{"-i", "x"})
+		var e *ConversionError
+		if !errors.As(err, &e) || e.Name != "int" || e.Alias != "i" || e.Argument != "x" || e.Type != "int" {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("Underlying error not wrapped: %#v", e.Err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorConvertToInt, "i", "x") {
+			t.Errorf("Unexpected message: %s", err)
+		}
+
+		level := logLevel("info")
+		opt := New()
+		opt.Var(&level, "level")
+		_, err = opt.Parse([]string{"--level", "x"})
+		if !errors.As(err, &e) || e.Type != "level" || e.Err == nil || e.Err.Error() != "unknown level" {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("InvalidValueError", func(t *testing.T) {
+		opt := New()
+		opt.String("format", "json", opt.Alias("f"), opt.ValidValues("json", "yaml"))
+		_, err := opt.Parse([]string{"-f", "xml"})
+		var e *InvalidValueError
+		if !errors.As(err, &e) || e.Name != "format" || e.Alias != "f" || e.Argument != "xml" || !reflect.DeepEqual(e.ValidValues, []string{"json", "yaml"}) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorInvalidValue, "f", "xml", []string{"json", "yaml"}) {
+			t.Errorf("Unexpected message: %s", err)
+		}
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		errPort := errors.New("port must be 1-65535")
+		opt := New()
+		opt.Int("port", 0, opt.Alias("p"), opt.Validate(func(value interface{}) error { return errPort }))
+		_, err := opt.Parse([]string{"-p", "0"})
+		var e *ValidationError
+		if !errors.As(err, &e) || e.Name != "port" || e.Alias != "p" {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if !errors.Is(err, errPort) {
+			t.Errorf("Validation error not wrapped: %#v", e.Err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorValidation, "p", errPort) {
+			t.Errorf("Unexpected message: %s", err)
+		}
+	})
+
+	t.Run("TooManyArgumentsError", func(t *testing.T) {
+		_, err := setup().Parse([]string{"-i", "1", "a", "b", "c"})
+		var e *TooManyArgumentsError
+		if !errors.As(err, &e) || !reflect.DeepEqual(e.Arguments, []string{"b", "c"}) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorTooManyArguments, []string{"b", "c"}) {
+			t.Errorf("Unexpected message: %s", err)
+		}
+	})
+
+	t.Run("MissingRequiredError", func(t *testing.T) {
+		_, err := setup().Parse([]string{})
+		var e *MissingRequiredError
+		if !errors.As(err, &e) || e.Name != "int" || e.IsArgument {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorMissingRequiredOption, "int") {
+			t.Errorf("Unexpected message: %s", err)
+		}
+
+		_, err = setup().Parse([]string{"-i", "1"})
+		if !errors.As(err, &e) || e.Name != "file" || !e.IsArgument {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		if err.Error() != fmt.Sprintf(text.ErrorMissingRequiredArgument, "file") {
+			t.Errorf("Unexpected message: %s", err)
+		}
+	})
+}
+
+func TestSuggestions(t *testing.T) {
+	setup := func() *GetOpt {
+		opt := New()
+		opt.SetRequireOrder()
+		opt.Bool("debug", false, opt.Alias("d"))
+		opt.String("profile", "", opt.Alias("p"))
+		opt.String("region", "")
+		opt.NewCommand("show", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.NewCommand("shop", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.NewCommand("log", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		return opt
+	}
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{"typo", []string{"--profiel"}, []string{"--profile"}},
+		{"missing letter", []string{"--debg"}, []string{"--debug"}},
+		{"case", []string{"--Region"}, []string{"--region"}},
+		{"no match", []string{"--verbose"}, []string{}},
+		{"single letter", []string{"-x"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := setup().Parse(tt.input)
+			var e *UnknownOptionError
+			if !errors.As(err, &e) || !reflect.DeepEqual(e.Suggestions, tt.expected) {
+				t.Fatalf("Unexpected error: %#v", err)
+			}
+		})
+	}
+
+	_, err := setup().Parse([]string{"--profiel"})
+	expected := fmt.Sprintf(text.MessageOnUnknown, "profiel") + "\n" + fmt.Sprintf(text.MessageDidYouMean, "--profile")
+	if err == nil || err.Error() != expected {
+		t.Errorf("Unexpected error: got '%v', expected '%s'", err, expected)
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := setup()
+		opt.Writer = buf
+		opt.SetUnknownMode(Warn)
+		_, err := opt.Parse([]string{"--debg"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := "WARNING: " + fmt.Sprintf(text.MessageOnUnknown, "debg") + "\n" + fmt.Sprintf(text.MessageDidYouMean, "--debug") + "\n"
+		if buf.String() != expected {
+			t.Errorf("Unexpected warning: got '%s', expected '%s'", buf.String(), expected)
+		}
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		opt := setup()
+		err := opt.Dispatch(context.Background(), "help", []string{"shwo"})
+		var e *UnknownCommandError
+		if !errors.As(err, &e) || e.Name != "shwo" || !reflect.DeepEqual(e.Suggestions, []string{"show", "shop"}) {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+		expected := fmt.Sprintf(text.ErrorNotACommand, "shwo") + "\n" + fmt.Sprintf(text.MessageDidYouMean, "show, shop")
+		if err.Error() != expected {
+			t.Errorf("Unexpected error: got '%s', expected '%s'", err, expected)
+		}
+
+		err = opt.Dispatch(context.Background(), "help", []string{"--lgo"})
+		if !errors.As(err, &e) || len(e.Suggestions) != 0 || err.Error() != fmt.Sprintf(text.ErrorNotACommandOrOption, "--lgo") {
+			t.Fatalf("Unexpected error: %#v", err)
+		}
+
+		err = opt.Dispatch(context.Background(), "help", []string{"help", "lgo"})
+		expected = fmt.Sprintf(text.ErrorUnknownHelpEntry, "lgo") + "\n" + fmt.Sprintf(text.MessageDidYouMean, "log")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error: got '%v', expected '%s'", err, expected)
+		}
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, tt := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"show", "show", 0},
+		{"show", "shwo", 1},
+		{"kitten", "sitting", 3},
+		{"profile", "profiel", 1},
+		{"ca", "abc", 3},
+	} {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%s, %s): got %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
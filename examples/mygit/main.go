@@ -28,6 +28,7 @@ func program() int {
 	gitlog.New(opt).SetCommandFn(gitlog.Run)
 	gitshow.New(opt).SetCommandFn(gitshow.Run)
 	gitslow.New(opt).SetCommandFn(gitslow.Run)
+	opt.CompletionCommand()
 	opt.HelpCommand("")
 	remaining, err := opt.Parse(os.Args[1:])
 	if err != nil {
@@ -10,6 +10,7 @@ package getoptions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -21,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/zhizh/go-getoptions/completion"
 	"github.com/zhizh/go-getoptions/help"
@@ -62,6 +64,7 @@ const (
 	HelpSynopsis
 	HelpCommandList
 	HelpOptionList
+	HelpArgumentList
 )
 
 // ErrorHelpCalled - Indicates the help has been handled.
@@ -82,6 +85,10 @@ type GetOpt struct {
 	synopsisArgs string
 	selfCalled   bool
 
+	hidden        bool   // Excluded from the parent help and completion
+	deprecated    bool   // Prints a warning when called
+	deprecatedMsg string // Optional deprecation message
+
 	// isCommand
 	isCommand bool
 	// CommandFn
@@ -95,6 +102,8 @@ type GetOpt struct {
 	unknownMode    UnknownMode // Unknown option mode
 	requireOrder   bool        // Stop parsing on non option
 	mapKeysToLower bool        // Set Map keys lower case
+	configFile     string      // Config file to read option values from
+	noExit         bool        // Return sentinel errors instead of calling os.Exit
 
 	// Debugging
 	Writer io.Writer // io.Writer to write warnings to. Defaults to os.Stderr.
@@ -104,8 +113,20 @@ type GetOpt struct {
 	commands   map[string]*GetOpt
 	args       *argList
 	completion *completion.Node
+	groups     []*optionGroup   // option group constraints
+	arguments  []*option.Option // positional arguments in definition order
+}
+
+// optionGroup - Constraint between the options with the given names.
+type optionGroup struct {
+	kind  help.GroupKind
+	names []string
 }
 
+// Value - Interface implemented by user defined option types.
+// See `opt.Var`.
+type Value = option.Value
+
 // ModifyFn - Function signature for functions that modify an option.
 type ModifyFn func(*option.Option)
 
@@ -160,26 +181,84 @@ func (gopt *GetOpt) SetCommandFn(fn CommandFn) *GetOpt {
 	return gopt
 }
 
+// SetHidden - Excludes the command from the help and completion of its parent, including the help command completion.
+// The command can still be called.
+func (gopt *GetOpt) SetHidden() *GetOpt {
+	gopt.hidden = true
+	gopt.completion.Hidden = true
+	return gopt
+}
+
+// SetDeprecated - Marks the command as deprecated.
+// The command is flagged in the help of its parent and Dispatch prints a warning to the Writer when it is called.
+// The message is shown next to the warning, for example: "use 'mygit log' instead".
+func (gopt *GetOpt) SetDeprecated(msg string) *GetOpt {
+	gopt.deprecated = true
+	gopt.deprecatedMsg = msg
+	return gopt
+}
+
+// commandListDescription - Returns the command description shown in the parent help, flagging deprecated commands.
+func (gopt *GetOpt) commandListDescription() string {
+	if !gopt.deprecated {
+		return gopt.description
+	}
+	detail := fmt.Sprintf("(%s)", help.DeprecatedDetail(gopt.deprecatedMsg))
+	if gopt.description == "" {
+		return detail
+	}
+	return gopt.description + " " + detail
+}
+
+// warnDeprecated - Writes the deprecation warning for the option or command to the Writer.
+func (gopt *GetOpt) warnDeprecated(format, name, msg string) {
+	warning := fmt.Sprintf(format, name)
+	if msg != "" {
+		warning += ": " + msg
+	}
+	fmt.Fprintf(gopt.Writer, text.MessageWarning, warning)
+}
+
 func (gopt *GetOpt) completionAppendAliases(aliases []string) {
 	node := gopt.completion.GetChildByName("options")
 	for _, alias := range aliases {
-		if len(alias) == 1 {
-			node.Entries = append(node.Entries, "-"+alias)
-		} else {
-			node.Entries = append(node.Entries, "--"+alias)
+		appendEntry(node, option.Dashed(alias))
+	}
+}
+
+// completionRemoveAliases - Removes the aliases from the option completions.
+func (gopt *GetOpt) completionRemoveAliases(aliases []string) {
+	remove := map[string]bool{}
+	for _, alias := range aliases {
+		remove[option.Dashed(alias)] = true
+	}
+	for _, name := range []string{"options", "options-with-arg"} {
+		node := gopt.completion.GetChildByName(name)
+		entries := []string{}
+		for _, entry := range node.Entries {
+			if !remove[entry] {
+				entries = append(entries, entry)
+			}
 		}
+		node.Entries = entries
 	}
 }
 
 func (gopt *GetOpt) completionWithArgAppendAliases(aliases []string) {
 	node := gopt.completion.GetChildByName("options-with-arg")
 	for _, alias := range aliases {
-		if len(alias) == 1 {
-			node.Entries = append(node.Entries, "-"+alias)
-		} else {
-			node.Entries = append(node.Entries, "--"+alias)
+		appendEntry(node, option.Dashed(alias))
+	}
+}
+
+// appendEntry - Appends the entry to the completion node entries unless it is already there.
+func appendEntry(node *completion.Node, entry string) {
+	for _, e := range node.Entries {
+		if e == entry {
+			return
 		}
 	}
+	node.Entries = append(node.Entries, entry)
 }
 
 // Self - Set a custom name and description that will show in the automated help.
@@ -213,8 +292,7 @@ func (gopt *GetOpt) Dispatch(ctx context.Context, helpCommandName string, args [
 	if len(args) == 0 {
 		fmt.Fprint(gopt.Writer, gopt.Help())
 		fmt.Fprint(gopt.Writer, gopt.extraDetails()+"\n")
-		exitFn(1)
-		return nil
+		return gopt.exit(1, ErrorNoCommand)
 	}
 	switch args[0] {
 	case helpCommandName:
@@ -223,21 +301,21 @@ func (gopt *GetOpt) Dispatch(ctx context.Context, helpCommandName string, args [
 			for name, v := range gopt.commands {
 				if commandName == name {
 					fmt.Fprint(gopt.Writer, v.Help())
-					exitFn(1)
-					return nil
+					return gopt.exit(1, ErrorHelpCalled)
 				}
 			}
-			// TODO: Expose string as var?
-			return fmt.Errorf("unkown help entry '%s'", commandName)
+			return errors.New(withSuggestions(fmt.Sprintf(text.ErrorUnknownHelpEntry, commandName), gopt.commandSuggestions(commandName)))
 		}
 		fmt.Fprint(gopt.Writer, gopt.Help())
 		fmt.Fprint(gopt.Writer, gopt.extraDetails()+"\n")
-		exitFn(1)
-		return nil
+		return gopt.exit(1, ErrorHelpCalled)
 	default:
 		commandName := args[0]
 		for name, v := range gopt.commands {
 			if commandName == name {
+				if v.deprecated {
+					gopt.warnDeprecated(text.WarningDeprecatedCommand, getCommandName(v), v.deprecatedMsg)
+				}
 				if v.CommandFn != nil {
 					remaining, err := v.Parse(args[1:])
 					if len(v.commands) == 0 {
@@ -258,12 +336,9 @@ func (gopt *GetOpt) Dispatch(ctx context.Context, helpCommandName string, args [
 			}
 		}
 		if strings.HasPrefix(args[0], "-") {
-			// TODO: Expose string as var?
-			return fmt.Errorf(`not a command or a valid option: '%s'
-       Did you mean to pass it after the command?`, args[0])
+			return &UnknownCommandError{Name: args[0]}
 		}
-		// TODO: Expose string as var?
-		return fmt.Errorf("not a command: '%s'", args[0])
+		return &UnknownCommandError{Name: args[0], Suggestions: gopt.commandSuggestions(args[0])}
 	}
 }
 
@@ -336,20 +411,46 @@ func (gopt *GetOpt) Option(name string) *option.Option {
 
 // setOption - Internal only
 func (gopt *GetOpt) setOption(opts ...*option.Option) *GetOpt {
-	node := gopt.completion.GetChildByName("options")
 	nodeWithArg := gopt.completion.GetChildByName("options-with-arg")
 	for _, opt := range opts {
 		gopt.obj[opt.Name] = opt
-		if opt.OptType == option.BoolType {
-			// TODO: Add aliases
-			node.Entries = append(node.Entries, opt.Name)
-		} else {
-			nodeWithArg.Entries = append(nodeWithArg.Entries, opt.Name)
+		// Hidden options are parsed but not completed
+		if opt.IsHidden {
+			gopt.completionRemoveAliases(opt.Aliases)
+			continue
+		}
+		// Value completion for --option=<TAB> and --option <TAB>
+		if argNode := valueCompletionNode(opt); argNode != nil && opt.OptType != option.BoolType {
+			for _, alias := range opt.Aliases {
+				nodeWithArg.ArgCompletion[option.Dashed(alias)] = argNode
+			}
 		}
 	}
 	return gopt
 }
 
+// valueCompletionNode - Returns the node that completes the value of the option or positional argument.
+// Returns nil when the value has no completion.
+// Precedence higher to lower: opt.CompletionFn, opt.ValidValues, opt.CompleteDirs, opt.CompleteFiles.
+func valueCompletionNode(opt *option.Option) *completion.Node {
+	switch {
+	case opt.CompletionFn != nil:
+		return completion.NewCallbackNode(opt.Name, opt.CompletionFn)
+	case len(opt.ValidValues) > 0:
+		return completion.NewNode(opt.Name, completion.CustomNode, opt.ValidValues)
+	case opt.DirCompletion:
+		node := completion.NewNode(".", completion.DirListNode, nil)
+		node.IgnoreCase = opt.IgnoreCase
+		return node
+	case opt.FileCompletion:
+		node := completion.NewNode(".", completion.FileListNode, nil)
+		node.Patterns = opt.FilePatterns
+		node.IgnoreCase = opt.IgnoreCase
+		return node
+	}
+	return nil
+}
+
 // SetMode - Sets the Operation Mode.
 // The operation mode only affects options starting with a single dash '-'.
 // The available operation modes are: normal, bundling or singleDash.
@@ -419,6 +520,10 @@ func (gopt *GetOpt) SetUnknownMode(mode UnknownMode) *GetOpt {
 //
 // `--help` is not handled by `command` since there was a subcommand that caused the parsing to stop.
 // In this case, the `remaining` slice will contain `['subcommand', '--help']` and that can be passed directly to a subcommand's option parser.
+//
+// When parsing stops at a command defined with NewCommand, the required options, option groups and positional arguments
+// are verified when the command parses its arguments, see Dispatch.
+// Otherwise they are verified before returning, with the remaining arguments bound as positional arguments.
 func (gopt *GetOpt) SetRequireOrder() *GetOpt {
 	gopt.requireOrder = true
 	return gopt
@@ -460,13 +565,50 @@ func (gopt *GetOpt) Required(msg ...string) ModifyFn {
 	}
 }
 
+// ValidValues - Restricts the arguments accepted by the option to the given list.
+// Passing an argument that is not in the list will make `Parse` return an error listing the valid values.
+//
+// The valid values are shown in the automated help and offered as completions for the option argument.
+func (gopt *GetOpt) ValidValues(values ...string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetValidValues(values...)
+	}
+}
+
+// Validate - Add a function to validate the option value.
+// The function is called with the option value (the same as `opt.Value(name)`)
+// every time the option is set from the command line, an environment variable
+// or the config file, and when an option with an optional argument is called without one.
+//
+// When the option is not set, Parse calls the function with the default value.
+//
+// The returned error is wrapped with the alias used to call the option, or the option name for the default value.
+// For example:
+//
+//     opt.Int("port", 8080, opt.Validate(func(value interface{}) error {
+//         if p := value.(int); p < 1 || p > 65535 {
+//             return fmt.Errorf("port must be 1-65535")
+//         }
+//         return nil
+//     }))
+func (gopt *GetOpt) Validate(fn func(value interface{}) error) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetValidateFn(fn)
+	}
+}
+
 // GetEnv - Will read an environment variable if set.
-// Precedence higher to lower: CLI option, environment variable, option default.
+// Precedence higher to lower: CLI option, environment variable, config file, option default.
+//
+// Currently, only single value options are supported: `opt.Bool`, `opt.String`,
+// `opt.Int`, `opt.Int64`, `opt.Uint`, `opt.Uint64`, `opt.Float64`, `opt.Duration`
+// and `opt.Var` (and their Var and Optional variants).
 //
-// Currently, only `opt.Bool`, `opt.BoolVar`, `opt.String`, and `opt.StringVar` are supported.
+// The environment variable is read by Parse, after the command line options.
+// Its value goes through the same checks as a command line argument: opt.ValidValues and opt.Validate.
 //
 // When an environment variable that matches the variable from opt.GetEnv is
-// set, opt.GetEnv will set opt.Called(name) to true and will set
+// set, Parse will set opt.Called(name) to true and will set
 // opt.CalledAs(name) to the name of the environment variable used.
 // In other words, when an option is required (opt.Required is set) opt.GetEnv
 // satisfies that requirement.
@@ -475,25 +617,154 @@ func (gopt *GetOpt) Required(msg ...string) ModifyFn {
 // "true" or "false" are valid.  They can be provided in any casing, for
 // example: "true", "True" or "TRUE".
 //
+// An environment variable value that can't be converted to the option type,
+// including a bool value other than "true" or "false", makes Parse return a ConversionError.
+//
 // NOTE: Non supported option types behave with a No-Op when `opt.GetEnv` is defined.
 func (gopt *GetOpt) GetEnv(name string) ModifyFn {
 	return func(opt *option.Option) {
 		opt.SetEnvVar(name)
-		value := os.Getenv(name)
-		if value != "" {
-			switch opt.OptType {
-			case option.BoolType:
-				v := strings.ToLower(value)
-				if v == "true" || v == "false" {
-					opt.Save(v)
-					opt.SetCalled(name)
+	}
+}
+
+// applyEnvVars - Saves the values from the environment variables into the options that haven't been called.
+func (gopt *GetOpt) applyEnvVars() error {
+	// Sort for consistent error reporting
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt := gopt.obj[name]
+		if opt.EnvVar == "" || opt.Called {
+			continue
+		}
+		value := os.Getenv(opt.EnvVar)
+		if value == "" {
+			continue
+		}
+		switch opt.OptType {
+		case option.BoolType:
+			if v := strings.ToLower(value); v != "true" && v != "false" {
+				return &ConversionError{Name: opt.Name, Alias: opt.EnvVar, Argument: value, Type: "bool"}
+			}
+			value = strings.ToLower(value)
+		case option.StringType, option.IntType, option.Float64Type, option.ValueType,
+			option.Int64Type, option.UintType, option.Uint64Type, option.DurationType:
+		default:
+			continue
+		}
+		// Errors refer to the env var
+		opt.UsedAlias = opt.EnvVar
+		err := opt.Save(value)
+		if err != nil {
+			return err
+		}
+		opt.SetCalled(opt.EnvVar)
+		if opt.IsDeprecated {
+			gopt.warnDeprecated(text.WarningDeprecatedOption, opt.EnvVar, opt.DeprecatedMsg)
+		}
+	}
+	return nil
+}
+
+// validateDefaults - Calls the validation function of the options that haven't been called with their default value.
+func (gopt *GetOpt) validateDefaults() error {
+	// Sort for consistent error reporting
+	names := []string{}
+	for name := range gopt.obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		opt := gopt.obj[name]
+		if opt.Called || opt.ValidateFn == nil {
+			continue
+		}
+		err := opt.ValidateFn(opt.Value())
+		if err != nil {
+			return &ValidationError{Name: opt.Name, Alias: opt.Name, Err: err}
+		}
+	}
+	return nil
+}
+
+// newGroup - Panics if any of the options in the group is not defined.
+// This is not an error because the programmer has to fix this!
+func (gopt *GetOpt) newGroup(kind help.GroupKind, names []string) {
+	if len(names) < 2 {
+		panic(fmt.Sprintf("Option group %v requires at least two options", names))
+	}
+	for _, name := range names {
+		if _, ok := gopt.obj[name]; !ok {
+			panic(fmt.Sprintf("Option group %v uses option '%s' which is not defined", names, name))
+		}
+	}
+	gopt.groups = append(gopt.groups, &optionGroup{kind: kind, names: names})
+}
+
+// MutuallyExclusive - Automatically return an error if more than one of the given options is called.
+// For example, `--json` and `--yaml` can't be combined.
+// The options are shown together in the help synopsis: `[--json | --yaml]`.
+//
+// NOTE: Define after the options have been defined.
+func (gopt *GetOpt) MutuallyExclusive(names ...string) *GetOpt {
+	gopt.newGroup(help.MutuallyExclusiveGroup, names)
+	return gopt
+}
+
+// RequiredTogether - Automatically return an error if some, but not all, of the given options are called.
+// For example, `--user` and `--password` must appear together.
+// The options are shown together in the help synopsis: `[--user <string> --password <string>]`.
+//
+// NOTE: Define after the options have been defined.
+func (gopt *GetOpt) RequiredTogether(names ...string) *GetOpt {
+	gopt.newGroup(help.RequiredTogetherGroup, names)
+	return gopt
+}
+
+// AtLeastOneOf - Automatically return an error if none of the given options is called.
+// The options are shown together in the help synopsis: `(--json | --yaml)`.
+//
+// NOTE: Define after the options have been defined.
+func (gopt *GetOpt) AtLeastOneOf(names ...string) *GetOpt {
+	gopt.newGroup(help.AtLeastOneOfGroup, names)
+	return gopt
+}
+
+// checkGroups - Returns an error if any of the option group constraints of the command or its parents is not met.
+// Commands accept the options of their parents, so the parent groups apply to them as well.
+func (gopt *GetOpt) checkGroups() error {
+	for g := gopt; g != nil; g = g.parent {
+		for _, group := range g.groups {
+			called := []*option.Option{}
+			missing := []*option.Option{}
+			for _, name := range group.names {
+				opt := g.obj[name]
+				if opt.Called {
+					called = append(called, opt)
+				} else {
+					missing = append(missing, opt)
+				}
+			}
+			switch group.kind {
+			case help.MutuallyExclusiveGroup:
+				if len(called) > 1 {
+					return fmt.Errorf(text.ErrorMutuallyExclusiveOptions, called[0].UsedAlias, called[1].UsedAlias)
+				}
+			case help.RequiredTogetherGroup:
+				if len(called) > 0 && len(missing) > 0 {
+					return fmt.Errorf(text.ErrorRequiredTogetherOptions, called[0].UsedAlias, missing[0].Name)
+				}
+			case help.AtLeastOneOfGroup:
+				if len(called) == 0 {
+					return fmt.Errorf(text.ErrorAtLeastOneOfOptions, group.names)
 				}
-			case option.StringType, option.IntType, option.Float64Type:
-				opt.Save(value)
-				opt.SetCalled(name)
 			}
 		}
 	}
+	return nil
 }
 
 // Description - Add a description to an option for use in automated help.
@@ -503,6 +774,25 @@ func (gopt *GetOpt) Description(msg string) ModifyFn {
 	}
 }
 
+// Hidden - Excludes the option from the automated help and completion.
+// The option can still be passed on the command line.
+func (gopt *GetOpt) Hidden() ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetHidden()
+	}
+}
+
+// Deprecated - Marks the option as deprecated.
+// The option is flagged in the automated help and a warning is printed to the Writer the first time it is used:
+// passed on the command line, or set from an environment variable or the config file.
+// The warning refers to the option as it was called, see opt.CalledAs.
+// The message is shown next to the warning, for example: "use --profile instead".
+func (gopt *GetOpt) Deprecated(msg string) ModifyFn {
+	return func(opt *option.Option) {
+		opt.SetDeprecated(msg)
+	}
+}
+
 // ArgName - Add an argument name to an option for use in automated help.
 // For example, by default a string option will have a default synopsis as follows:
 //
@@ -536,7 +826,7 @@ func getCommandName(opt *GetOpt) string {
 func (gopt *GetOpt) Help(sections ...HelpSection) string {
 	if len(sections) == 0 {
 		// Print all in the following order
-		sections = []HelpSection{helpDefaultName, HelpSynopsis, HelpCommandList, HelpOptionList}
+		sections = []HelpSection{helpDefaultName, HelpSynopsis, HelpCommandList, HelpArgumentList, HelpOptionList}
 	}
 	helpTxt := ""
 	var scriptName string
@@ -558,24 +848,46 @@ func (gopt *GetOpt) Help(sections ...HelpSection) string {
 		case HelpSynopsis:
 			options := []*option.Option{}
 			commands := []string{}
+			groups := []help.Group{}
 			for _, option := range gopt.obj {
 				options = append(options, option)
 			}
 			for _, command := range gopt.commands {
-				commands = append(commands, command.name)
+				if !command.hidden {
+					commands = append(commands, command.name)
+				}
 			}
-			helpTxt += help.Synopsis(scriptName, gopt.name, gopt.synopsisArgs, options, commands)
+			for _, group := range gopt.groups {
+				g := help.Group{Kind: group.kind}
+				for _, name := range group.names {
+					g.Options = append(g.Options, gopt.Option(name))
+				}
+				groups = append(groups, g)
+			}
+			args := gopt.synopsisArgs
+			if args == "" && len(gopt.arguments) > 0 {
+				args = help.ArgumentsSynopsis(gopt.arguments)
+			}
+			helpTxt += help.Synopsis(scriptName, gopt.name, args, options, commands, groups)
 			helpTxt += "\n"
 		case HelpCommandList:
 			m := make(map[string]string)
 			for _, command := range gopt.commands {
-				m[command.name] = command.description
+				if !command.hidden {
+					m[command.name] = command.commandListDescription()
+				}
 			}
 			commands := help.CommandList(m)
 			if commands != "" {
 				helpTxt += commands
 				helpTxt += "\n"
 			}
+		case HelpArgumentList:
+			arguments := help.ArgumentList(gopt.arguments)
+			if arguments != "" {
+				helpTxt += arguments
+				helpTxt += "\n"
+			}
 		case HelpOptionList:
 			options := []*option.Option{}
 			for _, option := range gopt.obj {
@@ -596,11 +908,19 @@ func (gopt *GetOpt) HelpCommand(description string) *GetOpt {
 	}
 	// TODO: "help" is hardcoded
 	opt := gopt.NewCommand("help", description)
-	commands := []string{}
-	for name := range gopt.commands {
-		commands = append(commands, name)
+	// The commands are listed at completion time, so commands defined or hidden afterwards are taken into account.
+	node := completion.NewNode("custom", completion.CustomNode, nil)
+	node.EntriesFn = func() []string {
+		commands := []string{}
+		for name, command := range gopt.commands {
+			if !command.hidden {
+				commands = append(commands, name)
+			}
+		}
+		sort.Strings(commands)
+		return commands
 	}
-	opt.CustomCompletion(commands)
+	opt.completion.AddChild(node)
 	return opt
 }
 
@@ -610,6 +930,14 @@ func (gopt *GetOpt) CustomCompletion(list []string) *GetOpt {
 	return gopt
 }
 
+// CustomCompletionFn - Add a function that returns the completions for the command arguments at completion time.
+// Use it for lists that change, for example git branches.
+// The function results are discarded if it doesn't return within `completion.CallbackTimeout`.
+func (gopt *GetOpt) CustomCompletionFn(fn func(ctx context.Context, prefix string) []string) *GetOpt {
+	gopt.completion.AddChild(completion.NewCallbackNode("custom-fn", fn))
+	return gopt
+}
+
 // BoolVar - define a `bool` option and its aliases.
 // The result will be available through the variable marked by the given pointer.
 // If the option is found, the result will be the opposite of the provided default.
@@ -652,16 +980,16 @@ func (gopt *GetOpt) handleSingleOption(name string, argument string, usedAlias s
 	if !gopt.args.existsNext() {
 		Debug.Printf("handleSingleOption %v %v\n", gopt.args.remaining(), gopt.args.existsNext())
 		if opt.IsOptional {
-			return nil
+			return opt.Validate()
 		}
-		return fmt.Errorf(text.ErrorMissingArgument, usedAlias)
+		return &MissingArgumentError{Name: name, Alias: usedAlias}
 	}
 	// Check if next arg is option
 	if optList, _ := isOption(gopt.args.peekNextValue(), gopt.mode); len(optList) > 0 {
 		if opt.IsOptional {
-			return nil
+			return opt.Validate()
 		}
-		return fmt.Errorf(text.ErrorArgumentWithDash, usedAlias)
+		return &MissingArgumentError{Name: name, Alias: usedAlias, Argument: gopt.args.peekNextValue()}
 	}
 	gopt.args.next()
 	return opt.Save(gopt.args.value())
@@ -692,6 +1020,24 @@ func (gopt *GetOpt) String(name, def string, fns ...ModifyFn) *string {
 	return &def
 }
 
+// StringEnumVar - define a `string` option that only accepts the given list of values.
+// The result will be available through the variable marked by the given pointer.
+// If not called, the return value will be that of the given default `def`.
+//
+// It is the same as calling `opt.StringVar` with `opt.ValidValues(values...)`.
+func (gopt *GetOpt) StringEnumVar(p *string, name, def string, values []string, fns ...ModifyFn) {
+	gopt.StringVar(p, name, def, append([]ModifyFn{gopt.ValidValues(values...)}, fns...)...)
+}
+
+// StringEnum - define a `string` option that only accepts the given list of values.
+// If not called, the return value will be that of the given default `def`.
+//
+// It is the same as calling `opt.String` with `opt.ValidValues(values...)`.
+func (gopt *GetOpt) StringEnum(name, def string, values []string, fns ...ModifyFn) *string {
+	gopt.StringEnumVar(&def, name, def, values, fns...)
+	return &def
+}
+
 // StringVarOptional - define a `string` option and its aliases.
 // The result will be available through the variable marked by the given pointer.
 //
@@ -832,6 +1178,258 @@ func (gopt *GetOpt) Float64Optional(name string, def float64, fns ...ModifyFn) *
 	return &def
 }
 
+// Int64Var - define an `int64` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) Int64Var(p *int64, name string, def int64, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.Int64Type, p)
+	opt.SetInt64(def)
+	opt.DefaultStr = fmt.Sprintf("%d", def)
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("int64")
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Int64 - define an `int64` option and its aliases.
+func (gopt *GetOpt) Int64(name string, def int64, fns ...ModifyFn) *int64 {
+	gopt.Int64Var(&def, name, def, fns...)
+	return &def
+}
+
+// Int64VarOptional - define an `int64` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// Int64VarOptional will set the int64 to the provided default value when no value is given.
+// For example, when called with `--int64Opt 123`, the value is `123`.
+// when called with `--int64Opt` the value is the given default.
+func (gopt *GetOpt) Int64VarOptional(p *int64, name string, def int64, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.Int64Type, p)
+	opt.SetInt64(def)
+	opt.DefaultStr = fmt.Sprintf("%d", def)
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("int64")
+	opt.IsOptional = true
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Int64Optional - define an `int64` option and its aliases.
+//
+// Int64Optional will set the int64 to the provided default value when no value is given.
+// For example, when called with `--int64Opt 123`, the value is `123`.
+// when called with `--int64Opt` the value is the given default.
+func (gopt *GetOpt) Int64Optional(name string, def int64, fns ...ModifyFn) *int64 {
+	gopt.Int64VarOptional(&def, name, def, fns...)
+	return &def
+}
+
+// UintVar - define a `uint` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) UintVar(p *uint, name string, def uint, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.UintType, p)
+	opt.SetUint(def)
+	opt.DefaultStr = fmt.Sprintf("%d", def)
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("uint")
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Uint - define a `uint` option and its aliases.
+func (gopt *GetOpt) Uint(name string, def uint, fns ...ModifyFn) *uint {
+	gopt.UintVar(&def, name, def, fns...)
+	return &def
+}
+
+// UintVarOptional - define a `uint` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// UintVarOptional will set the uint to the provided default value when no value is given.
+// For example, when called with `--uintOpt 123`, the value is `123`.
+// when called with `--uintOpt` the value is the given default.
+func (gopt *GetOpt) UintVarOptional(p *uint, name string, def uint, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.UintType, p)
+	opt.SetUint(def)
+	opt.DefaultStr = fmt.Sprintf("%d", def)
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("uint")
+	opt.IsOptional = true
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// UintOptional - define a `uint` option and its aliases.
+//
+// UintOptional will set the uint to the provided default value when no value is given.
+// For example, when called with `--uintOpt 123`, the value is `123`.
+// when called with `--uintOpt` the value is the given default.
+func (gopt *GetOpt) UintOptional(name string, def uint, fns ...ModifyFn) *uint {
+	gopt.UintVarOptional(&def, name, def, fns...)
+	return &def
+}
+
+// Uint64Var - define a `uint64` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+func (gopt *GetOpt) Uint64Var(p *uint64, name string, def uint64, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.Uint64Type, p)
+	opt.SetUint64(def)
+	opt.DefaultStr = fmt.Sprintf("%d", def)
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("uint64")
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Uint64 - define a `uint64` option and its aliases.
+func (gopt *GetOpt) Uint64(name string, def uint64, fns ...ModifyFn) *uint64 {
+	gopt.Uint64Var(&def, name, def, fns...)
+	return &def
+}
+
+// Uint64VarOptional - define a `uint64` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// Uint64VarOptional will set the uint64 to the provided default value when no value is given.
+// For example, when called with `--uint64Opt 123`, the value is `123`.
+// when called with `--uint64Opt` the value is the given default.
+func (gopt *GetOpt) Uint64VarOptional(p *uint64, name string, def uint64, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.Uint64Type, p)
+	opt.SetUint64(def)
+	opt.DefaultStr = fmt.Sprintf("%d", def)
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("uint64")
+	opt.IsOptional = true
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Uint64Optional - define a `uint64` option and its aliases.
+//
+// Uint64Optional will set the uint64 to the provided default value when no value is given.
+// For example, when called with `--uint64Opt 123`, the value is `123`.
+// when called with `--uint64Opt` the value is the given default.
+func (gopt *GetOpt) Uint64Optional(name string, def uint64, fns ...ModifyFn) *uint64 {
+	gopt.Uint64VarOptional(&def, name, def, fns...)
+	return &def
+}
+
+// DurationVar - define a `time.Duration` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// The argument is parsed with `time.ParseDuration`, for example: `300ms`, `1.5h` or `2h45m`.
+func (gopt *GetOpt) DurationVar(p *time.Duration, name string, def time.Duration, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.DurationType, p)
+	opt.SetDuration(def)
+	opt.DefaultStr = def.String()
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("duration")
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Duration - define a `time.Duration` option and its aliases.
+//
+// The argument is parsed with `time.ParseDuration`, for example: `300ms`, `1.5h` or `2h45m`.
+func (gopt *GetOpt) Duration(name string, def time.Duration, fns ...ModifyFn) *time.Duration {
+	gopt.DurationVar(&def, name, def, fns...)
+	return &def
+}
+
+// DurationVarOptional - define a `time.Duration` option and its aliases.
+// The result will be available through the variable marked by the given pointer.
+//
+// DurationVarOptional will set the duration to the provided default value when no value is given.
+// For example, when called with `--durationOpt 30s`, the value is `30s`.
+// when called with `--durationOpt` the value is the given default.
+func (gopt *GetOpt) DurationVarOptional(p *time.Duration, name string, def time.Duration, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.DurationType, p)
+	opt.SetDuration(def)
+	opt.DefaultStr = def.String()
+	opt.Handler = gopt.handleSingleOption
+	opt.SetHelpArgName("duration")
+	opt.IsOptional = true
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// DurationOptional - define a `time.Duration` option and its aliases.
+//
+// DurationOptional will set the duration to the provided default value when no value is given.
+// For example, when called with `--durationOpt 30s`, the value is `30s`.
+// when called with `--durationOpt` the value is the given default.
+func (gopt *GetOpt) DurationOptional(name string, def time.Duration, fns ...ModifyFn) *time.Duration {
+	gopt.DurationVarOptional(&def, name, def, fns...)
+	return &def
+}
+
+// Var - define an option of a user defined type and its aliases.
+// The type must implement the `Value` interface.
+// The result will be available through the given Value.
+//
+// The help synopsis uses `v.Type()` as the argument name and `v.String()` as the default.
+// For example:
+//
+//     type logLevel string
+//
+//     func (l *logLevel) Set(s string) error { ... }
+//     func (l *logLevel) String() string     { return string(*l) }
+//     func (l *logLevel) Type() string       { return "level" }
+//
+//     level := logLevel("info")
+//     opt.Var(&level, "log-level")
+func (gopt *GetOpt) Var(v Value, name string, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.ValueType, v)
+	opt.DefaultStr = v.String()
+	opt.Handler = gopt.handleSingleOption
+
+	for _, fn := range fns {
+		fn(opt)
+	}
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
 // StringSliceVar - define a `[]string` option and its aliases.
 //
 // StringSliceVar will accept multiple calls to the same option and append them
@@ -954,6 +1552,186 @@ func (gopt *GetOpt) IntSlice(name string, min, max int, fns ...ModifyFn) *[]int
 	return &s
 }
 
+// Int64SliceVar - define a `[]int64` option and its aliases.
+//
+// Int64SliceVar will accept multiple calls to the same option and append them
+// to the `[]int64`.
+// For example, when called with `--int64Rpt 1 --int64Rpt 2`, the value is `[]int64{1, 2}`.
+//
+// Additionally, it will allow to define a min and max amount of
+// arguments to be passed at once.
+// For example, when min is 1 and max is 3 and called with `--int64Rpt 1 2 3`,
+// the value is `[]int64{1, 2, 3}`.
+// It could also be called with `--int64Rpt 1 --int64Rpt 2 --int64Rpt 3` for the same result.
+//
+// When min is bigger than 1, it is required to pass the amount of arguments defined by min at once.
+// For example: with `min = 2`, you at least require `--int64Rpt 1 2 --int64Rpt 3`
+func (gopt *GetOpt) Int64SliceVar(p *[]int64, name string, min, max int, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.Int64RepeatType, p)
+	opt.DefaultStr = "[]"
+	opt.Handler = gopt.handleSliceMultiOption
+	opt.MinArgs = min
+	opt.MaxArgs = max
+	opt.SetHelpArgName("int64")
+	if min <= 0 {
+		panic(fmt.Sprintf("%s min should be > 0", name))
+	}
+	if max <= 0 || max < min {
+		panic(fmt.Sprintf("%s max should be > 0 and > min", name))
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	Debug.Printf("Int64Multi return: %v\n", *p)
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Int64Slice - define a `[]int64` option and its aliases.
+//
+// See Int64SliceVar for details.
+func (gopt *GetOpt) Int64Slice(name string, min, max int, fns ...ModifyFn) *[]int64 {
+	s := []int64{}
+	gopt.Int64SliceVar(&s, name, min, max, fns...)
+	return &s
+}
+
+// UintSliceVar - define a `[]uint` option and its aliases.
+//
+// UintSliceVar will accept multiple calls to the same option and append them
+// to the `[]uint`.
+// For example, when called with `--uintRpt 1 --uintRpt 2`, the value is `[]uint{1, 2}`.
+//
+// Additionally, it will allow to define a min and max amount of
+// arguments to be passed at once.
+// For example, when min is 1 and max is 3 and called with `--uintRpt 1 2 3`,
+// the value is `[]uint{1, 2, 3}`.
+// It could also be called with `--uintRpt 1 --uintRpt 2 --uintRpt 3` for the same result.
+//
+// When min is bigger than 1, it is required to pass the amount of arguments defined by min at once.
+// For example: with `min = 2`, you at least require `--uintRpt 1 2 --uintRpt 3`
+func (gopt *GetOpt) UintSliceVar(p *[]uint, name string, min, max int, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.UintRepeatType, p)
+	opt.DefaultStr = "[]"
+	opt.Handler = gopt.handleSliceMultiOption
+	opt.MinArgs = min
+	opt.MaxArgs = max
+	opt.SetHelpArgName("uint")
+	if min <= 0 {
+		panic(fmt.Sprintf("%s min should be > 0", name))
+	}
+	if max <= 0 || max < min {
+		panic(fmt.Sprintf("%s max should be > 0 and > min", name))
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	Debug.Printf("UintMulti return: %v\n", *p)
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// UintSlice - define a `[]uint` option and its aliases.
+//
+// See UintSliceVar for details.
+func (gopt *GetOpt) UintSlice(name string, min, max int, fns ...ModifyFn) *[]uint {
+	s := []uint{}
+	gopt.UintSliceVar(&s, name, min, max, fns...)
+	return &s
+}
+
+// Uint64SliceVar - define a `[]uint64` option and its aliases.
+//
+// Uint64SliceVar will accept multiple calls to the same option and append them
+// to the `[]uint64`.
+// For example, when called with `--uint64Rpt 1 --uint64Rpt 2`, the value is `[]uint64{1, 2}`.
+//
+// Additionally, it will allow to define a min and max amount of
+// arguments to be passed at once.
+// For example, when min is 1 and max is 3 and called with `--uint64Rpt 1 2 3`,
+// the value is `[]uint64{1, 2, 3}`.
+// It could also be called with `--uint64Rpt 1 --uint64Rpt 2 --uint64Rpt 3` for the same result.
+//
+// When min is bigger than 1, it is required to pass the amount of arguments defined by min at once.
+// For example: with `min = 2`, you at least require `--uint64Rpt 1 2 --uint64Rpt 3`
+func (gopt *GetOpt) Uint64SliceVar(p *[]uint64, name string, min, max int, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.Uint64RepeatType, p)
+	opt.DefaultStr = "[]"
+	opt.Handler = gopt.handleSliceMultiOption
+	opt.MinArgs = min
+	opt.MaxArgs = max
+	opt.SetHelpArgName("uint64")
+	if min <= 0 {
+		panic(fmt.Sprintf("%s min should be > 0", name))
+	}
+	if max <= 0 || max < min {
+		panic(fmt.Sprintf("%s max should be > 0 and > min", name))
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	Debug.Printf("Uint64Multi return: %v\n", *p)
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// Uint64Slice - define a `[]uint64` option and its aliases.
+//
+// See Uint64SliceVar for details.
+func (gopt *GetOpt) Uint64Slice(name string, min, max int, fns ...ModifyFn) *[]uint64 {
+	s := []uint64{}
+	gopt.Uint64SliceVar(&s, name, min, max, fns...)
+	return &s
+}
+
+// DurationSliceVar - define a `[]time.Duration` option and its aliases.
+//
+// DurationSliceVar will accept multiple calls to the same option and append them
+// to the `[]time.Duration`.
+// For example, when called with `--durationRpt 1s --durationRpt 2m`, the value is `[]time.Duration{time.Second, 2 * time.Minute}`.
+//
+// Additionally, it will allow to define a min and max amount of
+// arguments to be passed at once.
+// For example, when min is 1 and max is 3 and called with `--durationRpt 1s 2m 3h`,
+// the value is `[]time.Duration{time.Second, 2 * time.Minute, 3 * time.Hour}`.
+// It could also be called with `--durationRpt 1s --durationRpt 2m --durationRpt 3h` for the same result.
+//
+// When min is bigger than 1, it is required to pass the amount of arguments defined by min at once.
+// For example: with `min = 2`, you at least require `--durationRpt 1s 2m --durationRpt 3h`
+func (gopt *GetOpt) DurationSliceVar(p *[]time.Duration, name string, min, max int, fns ...ModifyFn) {
+	gopt.failIfDefined([]string{name})
+	opt := option.New(name, option.DurationRepeatType, p)
+	opt.DefaultStr = "[]"
+	opt.Handler = gopt.handleSliceMultiOption
+	opt.MinArgs = min
+	opt.MaxArgs = max
+	opt.SetHelpArgName("duration")
+	if min <= 0 {
+		panic(fmt.Sprintf("%s min should be > 0", name))
+	}
+	if max <= 0 || max < min {
+		panic(fmt.Sprintf("%s max should be > 0 and > min", name))
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+	Debug.Printf("DurationMulti return: %v\n", *p)
+	gopt.completionWithArgAppendAliases(opt.Aliases)
+	gopt.setOption(opt)
+}
+
+// DurationSlice - define a `[]time.Duration` option and its aliases.
+//
+// See DurationSliceVar for details.
+func (gopt *GetOpt) DurationSlice(name string, min, max int, fns ...ModifyFn) *[]time.Duration {
+	s := []time.Duration{}
+	gopt.DurationSliceVar(&s, name, min, max, fns...)
+	return &s
+}
+
 // StringMapVar - define a `map[string]string` option and its aliases.
 //
 // StringMapVar will accept multiple calls of `key=value` type to the same option
@@ -1041,14 +1819,14 @@ func (gopt *GetOpt) handleSliceMultiOption(name string, argument string, usedAli
 		Debug.Printf("total arguments: %d, index: %d, counter %d", gopt.args.size(), gopt.args.index(), argCounter)
 		if !gopt.args.existsNext() {
 			if required {
-				return fmt.Errorf(text.ErrorMissingArgument, name)
+				return &MissingArgumentError{Name: name, Alias: name}
 			}
-			return fmt.Errorf("NoMoreArguments")
+			return errNoMoreArguments
 		}
 		// Check if next arg is option
 		if optList, _ := isOption(gopt.args.peekNextValue(), gopt.mode); len(optList) > 0 {
 			Debug.Printf("Next arg is option: %s\n", gopt.args.peekNextValue())
-			return fmt.Errorf(text.ErrorArgumentWithDash, name)
+			return &MissingArgumentError{Name: name, Alias: name, Argument: gopt.args.peekNextValue()}
 		}
 		// Check if next arg is not key=value
 		if opt.OptType == option.StringMapType && !strings.Contains(gopt.args.peekNextValue(), "=") {
@@ -1057,9 +1835,22 @@ func (gopt *GetOpt) handleSliceMultiOption(name string, argument string, usedAli
 			}
 			return nil
 		}
-		if opt.OptType == option.IntRepeatType {
-			_, err := strconv.Atoi(gopt.args.peekNextValue())
-			if !required && err != nil {
+		// Check if next arg can't be converted to the slice type
+		if !required {
+			var err error
+			switch opt.OptType {
+			case option.IntRepeatType:
+				_, err = strconv.Atoi(gopt.args.peekNextValue())
+			case option.Int64RepeatType:
+				_, err = strconv.ParseInt(gopt.args.peekNextValue(), 10, 64)
+			case option.UintRepeatType:
+				_, err = strconv.ParseUint(gopt.args.peekNextValue(), 10, 0)
+			case option.Uint64RepeatType:
+				_, err = strconv.ParseUint(gopt.args.peekNextValue(), 10, 64)
+			case option.DurationRepeatType:
+				_, err = time.ParseDuration(gopt.args.peekNextValue())
+			}
+			if err != nil {
 				return nil
 			}
 		}
@@ -1073,15 +1864,14 @@ func (gopt *GetOpt) handleSliceMultiOption(name string, argument string, usedAli
 		err := next(argCounter <= opt.MinArgs)
 		Debug.Printf("counter: %d, value: %v, err %v", argCounter, opt.Value(), err)
 		if err != nil {
-			if err.Error() == "NoMoreArguments" {
+			if err == errNoMoreArguments {
 				Debug.Printf("return value: %v", opt.Value())
 				return nil
 			}
 			// always fail if errors under min args
 			// After min args, skip missing arg errors
-			if argCounter <= opt.MinArgs ||
-				(err.Error() != fmt.Sprintf(text.ErrorMissingArgument, name) &&
-					err.Error() != fmt.Sprintf(text.ErrorArgumentWithDash, name)) {
+			var missingArgumentErr *MissingArgumentError
+			if argCounter <= opt.MinArgs || !errors.As(err, &missingArgumentErr) {
 				Debug.Printf("return value: %v, err: %v", opt.Value(), err)
 				return err
 			}
@@ -1131,7 +1921,7 @@ func (gopt *GetOpt) Stringer() string {
 	for name, opt := range gopt.obj {
 		s += fmt.Sprintf("\"%s\":", name)
 		switch v := opt.Value().(type) {
-		case bool, int, float64:
+		case bool, int, int64, uint, uint64, float64:
 			s += fmt.Sprintf("%v,\n", v)
 		default:
 			s += fmt.Sprintf("\"%v\",\n", v)
@@ -1232,7 +2022,7 @@ func (gopt *GetOpt) getOptionFromAliases(alias string) (optName, usedAlias strin
 
 		if len(combined) >= 2 {
 			sort.Strings(combined)
-			return optName, usedAlias, found, fmt.Errorf(text.ErrorAmbiguousArgument, alias, combined)
+			return optName, usedAlias, found, &AmbiguousOptionError{Alias: alias, Candidates: combined}
 		}
 		if len(matches) == 1 {
 			found = true
@@ -1268,14 +2058,22 @@ func (gopt *GetOpt) passOptionsToChildren() error {
 		// pass options to child
 		for optName, opt := range gopt.obj {
 			commandOpt.obj[optName] = opt
+		}
 
-			parentNode := gopt.completion.GetChildByName("options")
-			node := commandOpt.completion.GetChildByName("options")
-			node.Entries = append(node.Entries, parentNode.Entries...)
-
-			parentNodeWithArg := gopt.completion.GetChildByName("options-with-arg")
-			nodeWithArg := commandOpt.completion.GetChildByName("options-with-arg")
-			nodeWithArg.Entries = append(nodeWithArg.Entries, parentNodeWithArg.Entries...)
+		// pass option completions to child, the parent entries already include the ones from its own parents
+		parentNode := gopt.completion.GetChildByName("options")
+		node := commandOpt.completion.GetChildByName("options")
+		for _, entry := range parentNode.Entries {
+			appendEntry(node, entry)
+		}
+		parentNodeWithArg := gopt.completion.GetChildByName("options-with-arg")
+		nodeWithArg := commandOpt.completion.GetChildByName("options-with-arg")
+		for _, entry := range parentNodeWithArg.Entries {
+			appendEntry(nodeWithArg, entry)
+		}
+		// pass option argument completions to child
+		for entry, node := range parentNodeWithArg.ArgCompletion {
+			nodeWithArg.ArgCompletion[entry] = node
 		}
 		// Once we are done passing the options to the command, pass them along to its children.
 		commandOpt.passOptionsToChildren()
@@ -1291,18 +2089,45 @@ func (gopt *GetOpt) passArgsToParent() {
 	}
 }
 
+// Complete - Returns the completions for the command line up to the cursor position point, as the shell would get them.
+// A negative point, or one past the end of the line, completes the whole line.
+// The line starts with the program name, for example: "myprog --pro".
+//
+// It is meant for testing the completion tree without setting COMP_LINE, see the completiontest package.
+func (gopt *GetOpt) Complete(line string, point int) []string {
+	gopt.passOptionsToChildren()
+	return gopt.complete(line, point)
+}
+
+// complete - Returns the completions for the line up to the cursor position.
+func (gopt *GetOpt) complete(line string, point int) []string {
+	if point >= 0 && point < len(line) {
+		line = line[:point]
+	}
+	return gopt.completion.CompLineComplete(false, line)
+}
+
 func (gopt *GetOpt) parse(args []string) ([]string, error) {
 	compLine := os.Getenv("COMP_LINE")
 	// https://stackoverflow.com/a/33396628
 	if compLine != "" {
-		fmt.Fprintln(completionWriter, strings.Join(gopt.completion.CompLineComplete(false, compLine), "\n"))
-		exitFn(124) // programmable completion restarts from the beginning, with an attempt to find a new compspec for that command.
+		point, err := strconv.Atoi(os.Getenv("COMP_POINT"))
+		if err != nil {
+			point = -1
+		}
+		fmt.Fprintln(completionWriter, strings.Join(gopt.complete(compLine, point), "\n"))
+		// programmable completion restarts from the beginning, with an attempt to find a new compspec for that command.
+		if err := gopt.exit(124, ErrorCompletionDone); err != nil {
+			return nil, err
+		}
 	}
 	al := newArgList(args)
 	gopt.args = al
 	Debug.Printf("parse %s\n", gopt.name)
 	Debug.Printf("Parse args: %v(%d)\n", args, len(args))
 	var remaining []string
+	// Positional arguments are bound from the remaining arguments that are not unknown options passed through.
+	var operands []string
 	// opt.argsIndex is the index in the opt.args slice.
 	// Option handlers will have to know about it, to ask for the next element.
 	for gopt.args.next() {
@@ -1316,8 +2141,8 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 				// move index to next position (to not include '--') and return remaining.
 				gopt.args.next()
 				remaining = append(remaining, gopt.args.remaining()...)
-				Debug.Printf("return %v, %v", remaining, nil)
-				return remaining, nil
+				operands = append(operands, gopt.args.remaining()...)
+				return gopt.finishParse(remaining, operands)
 			}
 			Debug.Printf("Parse continue\n")
 			for _, optElement := range optList {
@@ -1331,29 +2156,38 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 					gopt.passArgsToParent()
 					opt := gopt.Option(optName)
 					handler := opt.Handler
+					// Repeated options warn only the first time they are called.
+					called := opt.Called
 					Debug.Printf("handler found: name %s, argument %s, index %d, list %s, args %v\n", optName, argument, gopt.args.index(), optList[0], gopt.args.remaining())
 					err := handler(optName, argument, usedAlias)
 					if err != nil {
 						Debug.Printf("handler return: value %v, return %v, %v", opt.Value(), nil, err)
 						return nil, err
 					}
+					if opt.IsDeprecated && !called {
+						gopt.warnDeprecated(text.WarningDeprecatedOption, option.Dashed(gopt.CalledAs(optName)), opt.DeprecatedMsg)
+					}
 				} else {
 					Debug.Printf("opt_list not found for '%s'\n", optElement)
 					switch gopt.unknownMode {
 					case Pass:
 						if gopt.requireOrder {
 							remaining = append(remaining, gopt.args.remaining()...)
+							for _, e := range gopt.args.remaining() {
+								if optList, _ := isOption(e, gopt.mode); len(optList) == 0 {
+									operands = append(operands, e)
+								}
+							}
 							Debug.Printf("Stop on unknown options %s\n", arg)
-							Debug.Printf("return %v, %v", remaining, nil)
-							return remaining, nil
+							return gopt.finishParse(remaining, operands)
 						}
 						remaining = append(remaining, arg)
 					case Warn:
 						// TODO: This WARNING can't be changed into another language. Hardcoded.
-						fmt.Fprintf(gopt.Writer, "WARNING: "+text.MessageOnUnknown+"\n", optElement)
+						fmt.Fprintf(gopt.Writer, "WARNING: %s\n", gopt.unknownOptionError(optElement))
 						remaining = append(remaining, arg)
 					default:
-						err := fmt.Errorf(text.MessageOnUnknown, optElement)
+						err := gopt.unknownOptionError(optElement)
 						Debug.Printf("return %v, %v", nil, err)
 						return nil, err
 					}
@@ -1363,12 +2197,50 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 			if gopt.requireOrder {
 				remaining = append(remaining, gopt.args.remaining()...)
 				Debug.Printf("Stop on non option: %s\n", arg)
-				Debug.Printf("return %v, %v", remaining, nil)
-				return remaining, nil
+				// The command validates the options and reads the config file once its own arguments are parsed,
+				// otherwise file values would be merged with the slice options passed after the command.
+				if _, ok := gopt.commands[arg]; ok {
+					// Environment variables only set single value options, read them so the program can use them before Dispatch.
+					err := gopt.applyEnvVars()
+					if err != nil {
+						Debug.Printf("return %v, %v", nil, err)
+						return nil, err
+					}
+					Debug.Printf("return %v, %v", remaining, nil)
+					return remaining, nil
+				}
+				operands = append(operands, gopt.args.remaining()...)
+				return gopt.finishParse(remaining, operands)
 			}
 			remaining = append(remaining, arg)
+			operands = append(operands, arg)
 		}
 	}
+	return gopt.finishParse(remaining, operands)
+}
+
+// finishParse - Validates the parsed options and binds the operands to the positional arguments.
+// The operands are the remaining arguments without the unknown options passed through with the Pass or Warn modes.
+// Every parse that doesn't stop at a command ends here.
+func (gopt *GetOpt) finishParse(remaining, operands []string) ([]string, error) {
+	// Options not set from the command line are read from the environment variables.
+	err := gopt.applyEnvVars()
+	if err != nil {
+		Debug.Printf("return %v, %v", nil, err)
+		return nil, err
+	}
+	// Options not set from the command line or environment variables are read from the config file.
+	err = gopt.applyConfigFile()
+	if err != nil {
+		Debug.Printf("return %v, %v", nil, err)
+		return nil, err
+	}
+	// Options that weren't set keep their default value, which must pass the validation function as well.
+	err = gopt.validateDefaults()
+	if err != nil {
+		Debug.Printf("return %v, %v", nil, err)
+		return nil, err
+	}
 	// After parsing all options, verify that all required options where called.
 	for _, option := range gopt.obj {
 		err := option.CheckRequired()
@@ -1377,6 +2249,18 @@ func (gopt *GetOpt) parse(args []string) ([]string, error) {
 			return nil, err
 		}
 	}
+	// Verify the option group constraints.
+	err = gopt.checkGroups()
+	if err != nil {
+		Debug.Printf("return %v, %v", nil, err)
+		return nil, err
+	}
+	// Bind the positional arguments.
+	err = gopt.bindArguments(operands)
+	if err != nil {
+		Debug.Printf("return %v, %v", nil, err)
+		return nil, err
+	}
 	Debug.Printf("return %v, %v", remaining, nil)
 	return remaining, nil
 }
@@ -15,6 +15,7 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -629,6 +630,21 @@ func TestEndOfParsing(t *testing.T) {
 	if !reflect.DeepEqual(remaining, []string{"hola", "mundo", "--world"}) {
 		t.Errorf("remaining didn't have expected value: %v != %v", remaining, []string{"hola", "mundo", "--world"})
 	}
+
+	// Options are validated when parsing stops at '--'
+	opt = New()
+	opt.Bool("json", false)
+	opt.Bool("yaml", false)
+	opt.String("host", "", opt.Required())
+	opt.MutuallyExclusive("json", "yaml")
+	_, err = opt.Parse([]string{"--json", "--yaml", "--", "x"})
+	if err == nil || !errors.As(err, new(*MissingRequiredError)) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	_, err = opt.Parse([]string{"--host", "h", "--json", "--yaml", "--", "x"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorMutuallyExclusiveOptions, "json", "yaml") {
+		t.Errorf("Unexpected error: %v", err)
+	}
 }
 
 func TestGetOptAliases(t *testing.T) {
@@ -971,6 +987,304 @@ func TestGetOptFloat64(t *testing.T) {
 	}
 }
 
+func TestGetOptInt64UintDuration(t *testing.T) {
+	cases := []struct {
+		name     string
+		setup    func(opt *GetOpt)
+		input    []string
+		expected interface{}
+	}{
+		{"int64", func(opt *GetOpt) { opt.Int64("opt", 0) }, []string{"--opt=-9223372036854775808"}, int64(-9223372036854775808)},
+		{"int64 default", func(opt *GetOpt) { opt.Int64("opt", 5) }, []string{}, int64(5)},
+		{"int64 optional", func(opt *GetOpt) { opt.Int64Optional("opt", 5) }, []string{"--opt"}, int64(5)},
+		{"int64 optional", func(opt *GetOpt) { opt.Int64Optional("opt", 5) }, []string{"--opt=6"}, int64(6)},
+		{"uint", func(opt *GetOpt) { opt.Uint("opt", 0) }, []string{"--opt", "123"}, uint(123)},
+		{"uint optional", func(opt *GetOpt) { opt.UintOptional("opt", 5) }, []string{"--opt"}, uint(5)},
+		{"uint64", func(opt *GetOpt) { opt.Uint64("opt", 0) }, []string{"--opt=18446744073709551615"}, uint64(18446744073709551615)},
+		{"uint64 optional", func(opt *GetOpt) { opt.Uint64Optional("opt", 5) }, []string{"--opt"}, uint64(5)},
+		{"duration", func(opt *GetOpt) { opt.Duration("opt", 0) }, []string{"--opt", "1m30s"}, 90 * time.Second},
+		{"duration default", func(opt *GetOpt) { opt.Duration("opt", time.Second) }, []string{}, time.Second},
+		{"duration optional", func(opt *GetOpt) { opt.DurationOptional("opt", time.Second) }, []string{"--opt"}, time.Second},
+		{"duration optional", func(opt *GetOpt) { opt.DurationOptional("opt", time.Second) }, []string{"--opt", "2s"}, 2 * time.Second},
+		{"int64 slice", func(opt *GetOpt) { opt.Int64Slice("opt", 1, 3) }, []string{"--opt", "1", "2", "3", "x"}, []int64{1, 2, 3}},
+		{"int64 slice", func(opt *GetOpt) { opt.Int64Slice("opt", 1, 3) }, []string{"--opt", "1", "2", "x"}, []int64{1, 2}},
+		{"uint slice", func(opt *GetOpt) { opt.UintSlice("opt", 1, 3) }, []string{"--opt", "1", "2", "--opt", "3"}, []uint{1, 2, 3}},
+		{"uint64 slice", func(opt *GetOpt) { opt.Uint64Slice("opt", 1, 3) }, []string{"--opt", "1", "2", "3", "4"}, []uint64{1, 2, 3}},
+		{"duration slice", func(opt *GetOpt) { opt.DurationSlice("opt", 1, 3) }, []string{"--opt", "1s", "2m", "x"}, []time.Duration{time.Second, 2 * time.Minute}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opt := New()
+			c.setup(opt)
+			_, err := opt.Parse(c.input)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(opt.Value("opt"), c.expected) {
+				t.Errorf("Wrong value: %#v != %#v", opt.Value("opt"), c.expected)
+			}
+		})
+	}
+
+	errCases := []struct {
+		name     string
+		setup    func(opt *GetOpt)
+		input    []string
+		expected string
+	}{
+		{"int64", func(opt *GetOpt) { opt.Int64("opt", 0) }, []string{"--opt", "1.5"}, fmt.Sprintf(text.ErrorConvertToInt64, "opt", "1.5")},
+		{"uint", func(opt *GetOpt) { opt.Uint("opt", 0) }, []string{"--opt=-1"}, fmt.Sprintf(text.ErrorConvertToUint, "opt", "-1")},
+		{"uint64", func(opt *GetOpt) { opt.Uint64("opt", 0) }, []string{"--opt", "x"}, fmt.Sprintf(text.ErrorConvertToUint64, "opt", "x")},
+		{"duration", func(opt *GetOpt) { opt.Duration("opt", 0) }, []string{"--opt", "10"}, fmt.Sprintf(text.ErrorConvertToDuration, "opt", "10")},
+		{"duration", func(opt *GetOpt) { opt.Duration("opt", 0) }, []string{"--opt"}, fmt.Sprintf(text.ErrorMissingArgument, "opt")},
+		{"int64 slice", func(opt *GetOpt) { opt.Int64Slice("opt", 1, 1) }, []string{"--opt", "x"}, fmt.Sprintf(text.ErrorConvertToInt64, "opt", "x")},
+		{"uint slice", func(opt *GetOpt) { opt.UintSlice("opt", 1, 1) }, []string{"--opt", "x"}, fmt.Sprintf(text.ErrorConvertToUint, "opt", "x")},
+		{"uint64 slice", func(opt *GetOpt) { opt.Uint64Slice("opt", 1, 1) }, []string{"--opt", "x"}, fmt.Sprintf(text.ErrorConvertToUint64, "opt", "x")},
+		{"duration slice", func(opt *GetOpt) { opt.DurationSlice("opt", 2, 2) }, []string{"--opt", "1s", "x"}, fmt.Sprintf(text.ErrorConvertToDuration, "opt", "x")},
+	}
+	for _, c := range errCases {
+		t.Run(c.name, func(t *testing.T) {
+			opt := New()
+			c.setup(opt)
+			_, err := opt.Parse(c.input)
+			if err == nil || err.Error() != c.expected {
+				t.Errorf("Error string didn't match expected value '%v'", err)
+			}
+		})
+	}
+
+	// Vars and env
+	os.Setenv("_get_opt_env_duration", "1h")
+	defer os.Unsetenv("_get_opt_env_duration")
+	var i64 int64
+	var u uint
+	var u64 uint64
+	var d time.Duration
+	var ds []time.Duration
+	opt := New()
+	opt.Int64Var(&i64, "int64", 1)
+	opt.UintVar(&u, "uint", 2)
+	opt.Uint64Var(&u64, "uint64", 3)
+	opt.DurationVar(&d, "duration", 4*time.Second, opt.GetEnv("_get_opt_env_duration"))
+	opt.DurationSliceVar(&ds, "durations", 1, 1)
+	_, err := opt.Parse([]string{"--uint", "5", "--durations", "5ms"})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if i64 != 1 || u != 5 || u64 != 3 || d != time.Hour || !reflect.DeepEqual(ds, []time.Duration{5 * time.Millisecond}) {
+		t.Errorf("Wrong values: %v, %v, %v, %v, %v", i64, u, u64, d, ds)
+	}
+	expected := `OPTIONS:
+    --duration <duration>     (default: 4s, env: _get_opt_env_duration)
+
+    --durations <duration>    (default: [])
+
+    --int64 <int64>           (default: 1)
+
+    --uint <uint>             (default: 2)
+
+    --uint64 <uint64>         (default: 3)
+
+`
+	if opt.Help(HelpOptionList) != expected {
+		t.Errorf("Unexpected help:\n%s", firstDiff(opt.Help(HelpOptionList), expected))
+	}
+}
+
+// logLevel - User defined type used to test opt.Var.
+type logLevel string
+
+func (l *logLevel) Set(s string) error {
+	switch s {
+	case "debug", "info", "error":
+		*l = logLevel(s)
+		return nil
+	}
+	return fmt.Errorf("unknown level")
+}
+
+func (l *logLevel) String() string { return string(*l) }
+
+func (l *logLevel) Type() string { return "level" }
+
+func TestGetOptVar(t *testing.T) {
+	setup := func() (*GetOpt, *logLevel) {
+		level := logLevel("info")
+		opt := New()
+		opt.Var(&level, "level", opt.Alias("l"))
+		return opt, &level
+	}
+
+	cases := []struct {
+		name  string
+		input []string
+		value logLevel
+	}{
+		{"default", []string{}, "info"},
+		{"equal", []string{"--level=debug"}, "debug"},
+		{"separate", []string{"--level", "error", "world"}, "error"},
+		{"alias", []string{"-l", "debug"}, "debug"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opt, level := setup()
+			_, err := opt.Parse(c.input)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if *level != c.value {
+				t.Errorf("Wrong value: %v != %v", *level, c.value)
+			}
+			if opt.Value("level").(Value).String() != string(c.value) {
+				t.Errorf("Wrong value: %v != %v", opt.Value("level"), c.value)
+			}
+		})
+	}
+
+	// Missing Argument errors
+	opt, _ := setup()
+	_, err := opt.Parse([]string{"--level"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorMissingArgument, "level") {
+		t.Errorf("Error string didn't match expected value '%v'", err)
+	}
+
+	// Conversion errors
+	opt, _ = setup()
+	_, err = opt.Parse([]string{"-l", "trace"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToValue, "l", "level", "trace", "unknown level") {
+		t.Errorf("Error string didn't match expected value '%v'", err)
+	}
+
+	// Env var
+	os.Setenv("_get_opt_env_level", "error")
+	defer os.Unsetenv("_get_opt_env_level")
+	level := logLevel("info")
+	opt = New()
+	opt.Var(&level, "level", opt.GetEnv("_get_opt_env_level"))
+	_, err = opt.Parse([]string{})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if level != "error" || opt.CalledAs("level") != "_get_opt_env_level" {
+		t.Errorf("Wrong value: %v, %s", level, opt.CalledAs("level"))
+	}
+
+	// Help
+	expected := `SYNOPSIS:
+    go-getoptions.test [--level <level>] [<args>]
+
+OPTIONS:
+    --level <level>    (default: info, env: _get_opt_env_level)
+
+`
+	if opt.Help() != expected {
+		t.Errorf("Unexpected help:\n%s", firstDiff(opt.Help(), expected))
+	}
+}
+
+func TestValidValues(t *testing.T) {
+	setup := func() (*GetOpt, *string) {
+		opt := New()
+		format := opt.StringEnum("format", "json", []string{"json", "yaml"}, opt.Alias("f"))
+		opt.StringSlice("list", 1, 1, opt.ValidValues("a", "b"))
+		return opt, format
+	}
+
+	opt, format := setup()
+	_, err := opt.Parse([]string{"-f", "yaml", "--list", "b"})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if *format != "yaml" || !reflect.DeepEqual(opt.Value("list"), []string{"b"}) {
+		t.Errorf("Wrong value: %v, %v", *format, opt.Value("list"))
+	}
+
+	opt, format = setup()
+	_, err = opt.Parse([]string{"--format=xml"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorInvalidValue, "format", "xml", []string{"json", "yaml"}) {
+		t.Errorf("Error string didn't match expected value '%v'", err)
+	}
+	if *format != "json" {
+		t.Errorf("Wrong value: %v", *format)
+	}
+
+	opt, _ = setup()
+	_, err = opt.Parse([]string{"--list", "c"})
+	if err == nil || err.Error() != fmt.Sprintf(text.ErrorInvalidValue, "list", "c", []string{"a", "b"}) {
+		t.Errorf("Error string didn't match expected value '%v'", err)
+	}
+
+	// Env vars are checked regardless of the definition order
+	os.Setenv("_get_opt_env_format", "xml")
+	for _, fns := range [][]ModifyFn{
+		{opt.GetEnv("_get_opt_env_format"), opt.ValidValues("json", "yaml")},
+		{opt.ValidValues("json", "yaml"), opt.GetEnv("_get_opt_env_format")},
+	} {
+		opt = New()
+		format = opt.String("format", "json", fns...)
+		_, err = opt.Parse([]string{})
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorInvalidValue, "_get_opt_env_format", "xml", []string{"json", "yaml"}) {
+			t.Errorf("Error string didn't match expected value '%v'", err)
+		}
+		if *format != "json" || opt.Called("format") {
+			t.Errorf("Wrong value: %v, %v", *format, opt.Called("format"))
+		}
+	}
+	os.Unsetenv("_get_opt_env_format")
+
+	opt, _ = setup()
+	expected := `OPTIONS:
+    --format|-f <string>    (default: "json", valid values: json|yaml)
+
+    --list <string>         (default: [], valid values: a|b)
+
+`
+	if opt.Help(HelpOptionList) != expected {
+		t.Errorf("Unexpected help:\n%s", firstDiff(opt.Help(HelpOptionList), expected))
+	}
+
+	// Completion
+	called := false
+	exitFn = func(code int) { called = true }
+	defer func() { exitFn = os.Exit }()
+	defer os.Setenv("COMP_LINE", "")
+	defer func() { completionWriter = os.Stdout }()
+	tests := []struct {
+		name     string
+		compLine string
+		expected string
+	}{
+		{"option", "test --f", "--format\n"},
+		{"option equal", "test --format=", "json\nyaml\n"},
+		{"option equal partial", "test --format=y", "yaml\n"},
+		{"alias equal", "test -f=j", "json\n"},
+		{"option separate", "test --format ", "json\nyaml\n"},
+		{"option separate partial", "test -f y", "yaml\n"},
+		{"command", "test log --format j", "json\n"},
+		{"after argument", "test --format json --l", "--list\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			buf := new(bytes.Buffer)
+			completionWriter = buf
+			os.Setenv("COMP_LINE", tt.compLine)
+			opt, _ := setup()
+			opt.NewCommand("log", "")
+			_, err := opt.Parse([]string{})
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !called {
+				t.Errorf("COMP_LINE set and exit wasn't called")
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Error\ngot: '%s', expected: '%s'\n", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
 // TODO: Allow passing : as the map divider
 func TestGetOptStringMap(t *testing.T) {
 	setup := func() *GetOpt {
@@ -1928,6 +2242,164 @@ OPTIONS:
 	}
 }
 
+func TestValidate(t *testing.T) {
+	portRange := func(value interface{}) error {
+		if p := value.(int); p < 1 || p > 65535 {
+			return fmt.Errorf("port must be 1-65535")
+		}
+		return nil
+	}
+	maxTwo := func(value interface{}) error {
+		if len(value.([]string)) > 2 {
+			return fmt.Errorf("too many hosts")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name  string
+		setup func(opt *GetOpt)
+		input []string
+		err   error
+	}{
+		{"valid", func(opt *GetOpt) { opt.Int("port", 0, opt.Validate(portRange)) }, []string{"--port", "80"}, nil},
+		{"default", func(opt *GetOpt) { opt.Int("port", 0, opt.Alias("p"), opt.Validate(portRange)) }, []string{},
+			fmt.Errorf(text.ErrorValidation, "port", "port must be 1-65535")},
+		{"valid default", func(opt *GetOpt) { opt.Int("port", 8080, opt.Validate(portRange)) }, []string{}, nil},
+		{"invalid", func(opt *GetOpt) { opt.Int("port", 0, opt.Alias("p"), opt.Validate(portRange)) }, []string{"-p", "0"},
+			fmt.Errorf(text.ErrorValidation, "p", "port must be 1-65535")},
+		{"conversion error first", func(opt *GetOpt) { opt.Int("port", 0, opt.Validate(portRange)) }, []string{"--port", "x"},
+			fmt.Errorf(text.ErrorConvertToInt, "port", "x")},
+		{"optional default", func(opt *GetOpt) { opt.IntOptional("port", 0, opt.Validate(portRange)) }, []string{"--port"},
+			fmt.Errorf(text.ErrorValidation, "port", "port must be 1-65535")},
+		{"optional default", func(opt *GetOpt) { opt.IntOptional("port", 0, opt.Validate(portRange)) }, []string{"--port", "--x"},
+			fmt.Errorf(text.ErrorValidation, "port", "port must be 1-65535")},
+		{"optional", func(opt *GetOpt) { opt.IntOptional("port", 0, opt.Validate(portRange)) }, []string{"--port", "22"}, nil},
+		{"slice", func(opt *GetOpt) { opt.StringSlice("host", 1, 3, opt.Validate(maxTwo)) }, []string{"--host", "a", "b"}, nil},
+		{"slice", func(opt *GetOpt) { opt.StringSlice("host", 1, 3, opt.Validate(maxTwo)) }, []string{"--host", "a", "b", "--host", "c"},
+			fmt.Errorf(text.ErrorValidation, "host", "too many hosts")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := New()
+			opt.Bool("x", false)
+			tt.setup(opt)
+			_, err := opt.Parse(tt.input)
+			if (err == nil) != (tt.err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Errorf("Unexpected error: got '%v', expected '%v'", err, tt.err)
+			}
+		})
+	}
+
+	t.Run("env", func(t *testing.T) {
+		os.Setenv("_get_opt_env_port", "70000")
+		defer os.Unsetenv("_get_opt_env_port")
+		// Validate is defined after GetEnv to ensure definition order does not matter
+		opt := New()
+		opt.Int("port", 0, opt.GetEnv("_get_opt_env_port"), opt.Validate(portRange))
+		_, err := opt.Parse([]string{})
+		expected := fmt.Sprintf(text.ErrorValidation, "_get_opt_env_port", "port must be 1-65535")
+		if err == nil || err.Error() != expected {
+			t.Errorf("Unexpected error: got '%v', expected '%v'", err, expected)
+		}
+
+		// CLI has precedence over the env var
+		opt = New()
+		opt.Int("port", 0, opt.GetEnv("_get_opt_env_port"), opt.Validate(portRange))
+		_, err = opt.Parse([]string{"--port", "22"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+	})
+}
+
+func TestOptionGroups(t *testing.T) {
+	setup := func() *GetOpt {
+		opt := New()
+		opt.Bool("json", false, opt.Alias("j"))
+		opt.Bool("yaml", false)
+		opt.String("user", "")
+		opt.String("password", "")
+		opt.String("host", "")
+		opt.String("socket", "")
+		opt.MutuallyExclusive("json", "yaml")
+		opt.RequiredTogether("user", "password")
+		opt.AtLeastOneOf("host", "socket")
+		return opt
+	}
+
+	tests := []struct {
+		name  string
+		input []string
+		err   error
+	}{
+		{"valid", []string{"--host", "h"}, nil},
+		{"valid", []string{"--json", "--socket", "s", "--user", "u", "--password", "p"}, nil},
+		{"mutually exclusive", []string{"--host", "h", "-j", "--yaml"}, fmt.Errorf(text.ErrorMutuallyExclusiveOptions, "j", "yaml")},
+		{"required together", []string{"--host", "h", "--password", "p"}, fmt.Errorf(text.ErrorRequiredTogetherOptions, "password", "user")},
+		{"at least one", []string{}, fmt.Errorf(text.ErrorAtLeastOneOfOptions, []string{"host", "socket"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := setup()
+			_, err := opt.Parse(tt.input)
+			if (err == nil) != (tt.err == nil) || (err != nil && err.Error() != tt.err.Error()) {
+				t.Errorf("Unexpected error: got '%v', expected '%v'", err, tt.err)
+			}
+		})
+	}
+
+	opt := setup()
+	expected := `SYNOPSIS:
+    go-getoptions.test [--json|-j | --yaml] [--user <string> --password <string>]
+                       (--host <string> | --socket <string>) [<args>]
+
+`
+	if opt.Help(HelpSynopsis) != expected {
+		t.Errorf("Unexpected synopsis:\n%s", firstDiff(opt.Help(HelpSynopsis), expected))
+	}
+
+	t.Run("parent groups apply to commands", func(t *testing.T) {
+		for _, args := range [][]string{{"--json", "--yaml", "log"}, {"log", "--json", "--yaml"}} {
+			opt := New()
+			opt.Bool("json", false)
+			opt.Bool("yaml", false)
+			opt.MutuallyExclusive("json", "yaml")
+			opt.SetRequireOrder()
+			opt.NewCommand("log", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+			remaining, err := opt.Parse(args)
+			if err == nil {
+				err = opt.Dispatch(context.Background(), "help", remaining)
+			}
+			expected := fmt.Sprintf(text.ErrorMutuallyExclusiveOptions, "json", "yaml")
+			if err == nil || err.Error() != expected {
+				t.Errorf("%v: unexpected error: got '%v', expected '%s'", args, err, expected)
+			}
+		}
+	})
+
+	t.Run("undefined option panics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Undefined option in group did not panic")
+			}
+		}()
+		opt := New()
+		opt.Bool("json", false)
+		opt.MutuallyExclusive("json", "yaml")
+	})
+	t.Run("single option panics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Single option group did not panic")
+			}
+		}()
+		opt := New()
+		opt.Bool("json", false)
+		opt.AtLeastOneOf("json")
+	})
+}
+
 func TestCompletion(t *testing.T) {
 	called := false
 	exitFn = func(code int) { called = true }
@@ -1937,6 +2409,7 @@ func TestCompletion(t *testing.T) {
 
 	cleanup := func() {
 		os.Setenv("COMP_LINE", "")
+		os.Setenv("COMP_POINT", "")
 		completionWriter = os.Stdout
 		called = false
 	}
@@ -1949,6 +2422,10 @@ func TestCompletion(t *testing.T) {
 		{"option", func() { os.Setenv("COMP_LINE", "test --f") }, "--flag\n"},
 		{"command", func() { os.Setenv("COMP_LINE", "test h") }, "help\n"},
 		{"command", func() { os.Setenv("COMP_LINE", "test help ") }, "log\nshow\n"},
+		{"point", func() { os.Setenv("COMP_LINE", "test h --flag"); os.Setenv("COMP_POINT", "6") }, "help\n"},
+		{"point end", func() { os.Setenv("COMP_LINE", "test --f"); os.Setenv("COMP_POINT", "8") }, "--flag\n"},
+		{"point invalid", func() { os.Setenv("COMP_LINE", "test --f"); os.Setenv("COMP_POINT", "x") }, "--flag\n"},
+		{"quoted", func() { os.Setenv("COMP_LINE", "test 'help' l") }, "log\n"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1971,6 +2448,55 @@ func TestCompletion(t *testing.T) {
 	}
 }
 
+func TestCompletionAliases(t *testing.T) {
+	called := false
+	exitFn = func(code int) { called = true }
+	defer func() { exitFn = os.Exit }()
+	defer os.Setenv("COMP_LINE", "")
+	defer func() { completionWriter = os.Stdout }()
+
+	tests := []struct {
+		name     string
+		compLine string
+		expected string
+	}{
+		{"aliases", "test -", "-d\n--debug\n-p\n--profile\n-v\n--verbose\n"},
+		{"abbreviation", "test --pro", "--profile\n"},
+		{"short alias", "test -p", "-p\n"},
+		{"command", "test log -", "-d\n--debug\n-f\n--follow\n-p\n--profile\n-v\n--verbose\n"},
+		{"subcommand", "test log tail -", "-d\n--debug\n-f\n--follow\n-n\n-p\n--profile\n-v\n--verbose\n"},
+		{"option argument", "test log tail -p ", "default\nprod\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := New()
+			opt.Bool("debug", false, opt.Alias("d"))
+			opt.String("profile", "", opt.Alias("p"), opt.ValidValues("default", "prod"))
+			opt.Increment("verbose", 0, opt.Alias("v"))
+			log := opt.NewCommand("log", "")
+			log.Bool("follow", false, opt.Alias("f"))
+			log.NewCommand("tail", "").Int("n", 10)
+			os.Setenv("COMP_LINE", tt.compLine)
+			// Parsing more than once must not duplicate the inherited entries
+			for i := 0; i < 2; i++ {
+				called = false
+				buf := new(bytes.Buffer)
+				completionWriter = buf
+				_, err := opt.Parse([]string{})
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				if !called {
+					t.Errorf("COMP_LINE set and exit wasn't called")
+				}
+				if buf.String() != tt.expected {
+					t.Errorf("Error\ngot: '%s', expected: '%s'\n", buf.String(), tt.expected)
+				}
+			}
+		})
+	}
+}
+
 // Verifies that a panic is reached when Command is called with a getoptions without a name.
 func TestCommandPanicWithNoNameInput(t *testing.T) {
 	defer func() {
@@ -2902,6 +3428,22 @@ func TestGetEnv(t *testing.T) {
 		t.Log(buf.String())
 		cleanup()
 	})
+	t.Run("bool env error", func(t *testing.T) {
+		setup("yes")
+		buf := setupLogging()
+		opt := New()
+		v1 := opt.Bool("opt1", false, opt.GetEnv("_get_opt_env_test1"))
+		_, err := opt.Parse([]string{})
+		var convErr *ConversionError
+		if !errors.As(err, &convErr) || err.Error() != fmt.Sprintf(text.ErrorConvertToBool, "_get_opt_env_test1", "yes") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if *v1 != false || opt.Called("opt1") {
+			t.Errorf("Unexpected value: %v, %#v", *v1, opt.Option("opt1"))
+		}
+		t.Log(buf.String())
+		cleanup()
+	})
 	t.Run("bool env true reverse", func(t *testing.T) {
 		setup("tRue")
 		buf := setupLogging()
@@ -3132,6 +3674,21 @@ func TestGetEnv(t *testing.T) {
 		t.Log(buf.String())
 		cleanup()
 	})
+	t.Run("env read before the command", func(t *testing.T) {
+		setup("true")
+		opt := New()
+		opt.SetRequireOrder()
+		opt.Bool("debug", false, opt.GetEnv("_get_opt_env_test1"))
+		opt.NewCommand("log", "")
+		remaining, err := opt.Parse([]string{"log"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(remaining, []string{"log"}) || !opt.Called("debug") || opt.CalledAs("debug") != "_get_opt_env_test1" {
+			t.Errorf("Unexpected result: %v, %v", remaining, opt.CalledAs("debug"))
+		}
+		cleanup()
+	})
 	t.Run("int env error", func(t *testing.T) {
 		setup("abc")
 		buf := setupLogging()
@@ -3140,11 +3697,10 @@ func TestGetEnv(t *testing.T) {
 		opt.IntVar(&v1, "opt1", 123, opt.GetEnv("_get_opt_env_test1"))
 		v2 := opt.Int("opt2", 123, opt.GetEnv("_get_opt_env_test2"))
 		_, err := opt.Parse([]string{})
-		// TODO: Handle errors when env vars don't have proper types
-		if err != nil {
-			t.Errorf("Unexpected error: %s", err)
+		if err == nil || err.Error() != fmt.Sprintf(text.ErrorConvertToInt, "_get_opt_env_test1", "abc") {
+			t.Errorf("Unexpected error: %v", err)
 		}
-		if v1 != 123 {
+		if v1 != 123 || opt.Called("opt1") {
 			t.Errorf("Unexpected value: %d, %#v", v1, opt.Option("opt1"))
 		}
 		if *v2 != 123 {
@@ -3336,3 +3892,206 @@ func TestInterruptContext(t *testing.T) {
 	}
 	t.Log(buf.String())
 }
+
+func TestHiddenDeprecated(t *testing.T) {
+	setup := func(buf *bytes.Buffer) *GetOpt {
+		opt := New()
+		opt.Self("test", "")
+		opt.Writer = buf
+		opt.Bool("debug", false)
+		opt.Bool("internal", false, opt.Hidden())
+		opt.String("profile", "", opt.Alias("p"))
+		opt.String("region", "", opt.Alias("r"), opt.Deprecated("use --profile instead"))
+		opt.NewCommand("log", "Show logs").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.NewCommand("debug-dump", "").SetHidden().SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.NewCommand("show", "Show objects").SetDeprecated("use 'test log' instead").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.HelpCommand("Show help")
+		return opt
+	}
+
+	t.Run("hidden and deprecated options parse", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := setup(buf)
+		_, err := opt.Parse([]string{"--internal", "-r", "us"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !opt.Called("internal") || opt.Value("region") != "us" {
+			t.Errorf("Options not parsed")
+		}
+		expected := fmt.Sprintf(text.MessageWarning, fmt.Sprintf(text.WarningDeprecatedOption, "-r")+": use --profile instead")
+		if buf.String() != expected {
+			t.Errorf("Unexpected warning: got %q, expected %q", buf.String(), expected)
+		}
+	})
+
+	t.Run("repeated deprecated option warns once", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		tags := opt.StringSlice("tag", 1, 1, opt.Alias("t"), opt.Deprecated(""))
+		_, err := opt.Parse([]string{"--tag", "a", "-t", "b", "--tag", "c"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(*tags, []string{"a", "b", "c"}) {
+			t.Errorf("Unexpected value: %v", *tags)
+		}
+		expected := fmt.Sprintf(text.MessageWarning, fmt.Sprintf(text.WarningDeprecatedOption, "--tag"))
+		if buf.String() != expected {
+			t.Errorf("Unexpected warning: got %q, expected %q", buf.String(), expected)
+		}
+	})
+
+	t.Run("deprecated option set from env and config", func(t *testing.T) {
+		os.Setenv("TEST_REGION", "us")
+		defer os.Unsetenv("TEST_REGION")
+		path := writeConfig(t, "config.ini", "zone = a\n")
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		opt.ConfigFile(path)
+		opt.String("region", "", opt.GetEnv("TEST_REGION"), opt.Deprecated("use --profile instead"))
+		opt.String("zone", "", opt.Deprecated(""))
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := fmt.Sprintf(text.MessageWarning, fmt.Sprintf(text.WarningDeprecatedOption, "TEST_REGION")+": use --profile instead") +
+			fmt.Sprintf(text.MessageWarning, fmt.Sprintf(text.WarningDeprecatedOption, path+":zone"))
+		if buf.String() != expected {
+			t.Errorf("Unexpected warning: got %q, expected %q", buf.String(), expected)
+		}
+	})
+
+	t.Run("no warning when not called", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := setup(buf)
+		_, err := opt.Parse([]string{"--debug"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if buf.String() != "" {
+			t.Errorf("Unexpected warning: %q", buf.String())
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		opt := setup(new(bytes.Buffer))
+		got := opt.Help()
+		expected := `NAME:
+    test
+
+SYNOPSIS:
+    test [--debug] [--profile|-p <string>] [--region|-r <string>]
+         <command> [<args>]
+
+COMMANDS:
+    help    Show help
+    log     Show logs
+    show    Show objects (deprecated: use 'test log' instead)
+
+OPTIONS:
+    --debug                  (default: false)
+
+    --profile|-p <string>    (default: "")
+
+    --region|-r <string>     (default: "", deprecated: use --profile instead)
+
+`
+		if got != expected {
+			t.Errorf("Unexpected help:\n%s", firstDiff(got, expected))
+			t.Errorf("got:\n%s\nexpected:\n%s\n", got, expected)
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		opt := setup(new(bytes.Buffer))
+		got := opt.Complete("test ", -1)
+		expected := []string{"log", "show", "help"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got %v, expected %v", got, expected)
+		}
+		got = opt.Complete("test -", -1)
+		expected = []string{"--debug", "-p", "--profile", "-r", "--region"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got %v, expected %v", got, expected)
+		}
+		got = opt.Complete("test help ", -1)
+		expected = []string{"help", "log", "show"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got %v, expected %v", got, expected)
+		}
+		// Commands defined or hidden after the help command
+		opt.NewCommand("status", "")
+		opt.commands["show"].SetHidden()
+		got = opt.Complete("test help ", -1)
+		expected = []string{"help", "log", "status"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("got %v, expected %v", got, expected)
+		}
+		got = opt.Complete("test --i", -1)
+		if len(got) != 0 {
+			t.Errorf("Unexpected completions: %v", got)
+		}
+	})
+
+	t.Run("scripts, pages and schema", func(t *testing.T) {
+		opt := setup(new(bytes.Buffer))
+		outputs := []string{opt.ManPage(1), opt.Doc(Markdown)}
+		for _, shell := range []string{"zsh", "fish"} {
+			script, err := opt.CompletionScript(shell)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			outputs = append(outputs, script)
+		}
+		for _, out := range outputs {
+			if strings.Contains(out, "internal") || strings.Contains(out, "debug-dump") || !strings.Contains(out, "region") {
+				t.Errorf("Unexpected output:\n%s", out)
+			}
+		}
+		if _, ok := opt.ManPages(1)["test-debug-dump.1"]; ok {
+			t.Errorf("Unexpected man page for hidden command")
+		}
+		schema := opt.Schema()
+		if !schema.Options[1].Hidden || schema.Options[1].Name != "internal" ||
+			!schema.Options[3].Deprecated || schema.Options[3].DeprecatedMsg != "use --profile instead" {
+			t.Errorf("Unexpected schema options: %#v", schema.Options)
+		}
+		if !schema.Commands[0].Hidden || schema.Commands[0].Name != "debug-dump" || !schema.Commands[3].Deprecated {
+			t.Errorf("Unexpected schema commands: %#v", schema.Commands)
+		}
+	})
+
+	t.Run("suggestions", func(t *testing.T) {
+		opt := setup(new(bytes.Buffer))
+		_, err := opt.Parse([]string{"--internl"})
+		var unknown *UnknownOptionError
+		if !errors.As(err, &unknown) || len(unknown.Suggestions) != 0 {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		err = opt.Dispatch(context.Background(), "help", []string{"debug-dum"})
+		var unknownCommand *UnknownCommandError
+		if !errors.As(err, &unknownCommand) || len(unknownCommand.Suggestions) != 0 {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("dispatch", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := setup(buf)
+		err := opt.Dispatch(context.Background(), "help", []string{"debug-dump"})
+		if err != nil || buf.String() != "" {
+			t.Errorf("Unexpected result: %v, %q", err, buf.String())
+		}
+		err = opt.Dispatch(context.Background(), "help", []string{"show"})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		expected := fmt.Sprintf(text.MessageWarning, fmt.Sprintf(text.WarningDeprecatedCommand, "test show")+": use 'test log' instead")
+		if buf.String() != expected {
+			t.Errorf("Unexpected warning: got %q, expected %q", buf.String(), expected)
+		}
+	})
+}
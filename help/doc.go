@@ -0,0 +1,214 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package help
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zhizh/go-getoptions/option"
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// docFormat - Markup used to render a reference document.
+type docFormat struct {
+	ext       string
+	title     func(s string) string
+	section   func(s string) string
+	code      func(s string) string // Inline code, safe to use in table cells
+	cell      func(s string) string // Escapes text for a table cell
+	link      func(name, file string) string
+	codeBlock func(s string) string
+	table     func(header []string, rows [][]string) string
+}
+
+var markdownFormat = docFormat{
+	ext:     ".md",
+	title:   func(s string) string { return fmt.Sprintf("# %s\n\n", s) },
+	section: func(s string) string { return fmt.Sprintf("## %s\n\n", s) },
+	code:    func(s string) string { return "`" + strings.ReplaceAll(s, "|", `\|`) + "`" },
+	cell: func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(s)
+	},
+	link:      func(name, file string) string { return fmt.Sprintf("[%s](%s)", name, file) },
+	codeBlock: func(s string) string { return fmt.Sprintf("```\n%s\n```\n\n", s) },
+	table: func(header []string, rows [][]string) string {
+		out := fmt.Sprintf("| %s |\n", strings.Join(header, " | "))
+		out += strings.Repeat("| --- ", len(header)) + "|\n"
+		for _, row := range rows {
+			out += fmt.Sprintf("| %s |\n", strings.Join(row, " | "))
+		}
+		return out + "\n"
+	},
+}
+
+var asciiDocFormat = docFormat{
+	ext:     ".adoc",
+	title:   func(s string) string { return fmt.Sprintf("= %s\n\n", s) },
+	section: func(s string) string { return fmt.Sprintf("== %s\n\n", s) },
+	code:    func(s string) string { return "`+" + strings.ReplaceAll(s, "|", `\|`) + "+`" },
+	cell: func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " +\n").Replace(s)
+	},
+	link:      func(name, file string) string { return fmt.Sprintf("xref:%s[%s]", file, name) },
+	codeBlock: func(s string) string { return fmt.Sprintf("----\n%s\n----\n\n", s) },
+	table: func(header []string, rows [][]string) string {
+		out := "[options=\"header\"]\n|===\n"
+		out += fmt.Sprintf("|%s\n", strings.Join(header, " |"))
+		for _, row := range rows {
+			out += "\n"
+			for _, c := range row {
+				out += fmt.Sprintf("|%s\n", c)
+			}
+		}
+		return out + "|===\n\n"
+	},
+}
+
+// synopsisLines - Returns the synopsis without the header and the indentation.
+func synopsisLines(synopsis string) []string {
+	synopsis = strings.TrimRight(synopsis, "\n")
+	synopsis = strings.TrimPrefix(synopsis, text.HelpSynopsisHeader+":\n")
+	lines := []string{}
+	for _, line := range strings.Split(synopsis, "\n") {
+		lines = append(lines, strings.TrimPrefix(line, strings.Repeat(" ", Indentation)))
+	}
+	return lines
+}
+
+// DocFormat - Markup language of a reference document.
+type DocFormat int
+
+// Reference document formats
+const (
+	MarkdownFormat DocFormat = iota
+	AsciiDocFormat
+)
+
+func (format DocFormat) markup() docFormat {
+	if format == AsciiDocFormat {
+		return asciiDocFormat
+	}
+	return markdownFormat
+}
+
+// DocFile - Returns the file name of the reference document for the program or command: "mygit log" -> "mygit-log.md".
+func DocFile(name string, format DocFormat) string {
+	return ManPageName(name) + format.markup().ext
+}
+
+// Doc - Return the reference document in the given format.
+//
+// The document has the SYNOPSIS, COMMANDS, ARGUMENTS and OPTIONS sections of the help output, with the
+// commands, arguments and options as tables, and links to the parent and child command documents.
+func Doc(page ManPage, format DocFormat) string {
+	return doc(page, format.markup())
+}
+
+func doc(page ManPage, f docFormat) string {
+	link := func(name, label string) string {
+		return f.link(label, ManPageName(name)+f.ext)
+	}
+	valueDetails := func(opt *option.Option) string {
+		description := opt.Description
+		if len(opt.ValidValues) > 0 {
+			if description != "" {
+				description += " "
+			}
+			description += fmt.Sprintf("(valid values: %s)", strings.Join(opt.ValidValues, "|"))
+		}
+		if opt.IsDeprecated {
+			if description != "" {
+				description += " "
+			}
+			description += fmt.Sprintf("(%s)", DeprecatedDetail(opt.DeprecatedMsg))
+		}
+		return f.cell(description)
+	}
+	required := func(opt *option.Option) string {
+		if opt.IsRequired {
+			return text.HelpDocRequiredValue
+		}
+		return ""
+	}
+
+	out := f.title(page.Name)
+	if description := strings.TrimSpace(page.Description); description != "" {
+		out += description + "\n\n"
+	}
+
+	if page.Synopsis != "" {
+		out += f.section(text.HelpSynopsisHeader)
+		out += f.codeBlock(strings.Join(synopsisLines(page.Synopsis), "\n"))
+	}
+
+	if len(page.Commands) > 0 {
+		out += f.section(text.HelpCommandsHeader)
+		names := []string{}
+		for name := range page.Commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		rows := [][]string{}
+		for _, name := range names {
+			rows = append(rows, []string{link(page.Name+" "+name, name), f.cell(page.Commands[name])})
+		}
+		out += f.table([]string{text.HelpDocCommandColumn, text.HelpDocDescriptionColumn}, rows)
+	}
+
+	if len(page.Arguments) > 0 {
+		out += f.section(text.HelpArgumentsHeader)
+		rows := [][]string{}
+		for _, arg := range page.Arguments {
+			def := ""
+			if !arg.IsRequired && arg.DefaultStr != "" && arg.DefaultStr != "[]" {
+				def = f.code(arg.DefaultStr)
+			}
+			rows = append(rows, []string{f.code(argumentSynopsis(arg)), f.code(arg.OptType.String()), def, required(arg), valueDetails(arg)})
+		}
+		out += f.table([]string{text.HelpDocArgumentColumn, text.HelpDocTypeColumn, text.HelpDocDefaultColumn,
+			text.HelpDocRequiredColumn, text.HelpDocDescriptionColumn}, rows)
+	}
+
+	requiredOptions, normalOptions := page.sortedOptions()
+	if len(requiredOptions)+len(normalOptions) > 0 {
+		out += f.section(text.HelpOptionsHeader)
+		rows := [][]string{}
+		for _, opt := range append(requiredOptions, normalOptions...) {
+			aliases := []string{}
+			for _, alias := range opt.Aliases {
+				if alias != opt.Name {
+					aliases = append(aliases, f.code(option.Dashed(alias)))
+				}
+			}
+			def := ""
+			if !opt.IsRequired {
+				def = f.code(opt.DefaultStr)
+			}
+			env := ""
+			if opt.EnvVar != "" {
+				env = f.code(opt.EnvVar)
+			}
+			rows = append(rows, []string{f.code(option.Dashed(opt.Name)), strings.Join(aliases, ", "), f.code(opt.OptType.String()),
+				def, env, required(opt), valueDetails(opt)})
+		}
+		out += f.table([]string{text.HelpDocOptionColumn, text.HelpDocAliasesColumn, text.HelpDocTypeColumn, text.HelpDocDefaultColumn,
+			text.HelpDocEnvColumn, text.HelpDocRequiredColumn, text.HelpDocDescriptionColumn}, rows)
+	}
+
+	if len(page.SeeAlso) > 0 {
+		out += f.section(text.HelpSeeAlsoHeader)
+		for _, name := range page.SeeAlso {
+			out += fmt.Sprintf("* %s\n", link(name, name))
+		}
+		out += "\n"
+	}
+	return strings.TrimRight(out, "\n") + "\n"
+}
@@ -0,0 +1,86 @@
+package help
+
+import (
+	"testing"
+
+	"github.com/zhizh/go-getoptions/option"
+)
+
+func TestDoc(t *testing.T) {
+	profile := "default"
+	token := ""
+	file := ""
+
+	page := ManPage{
+		Name:        "tool log",
+		Description: "Show logs.",
+		Synopsis:    "SYNOPSIS:\n    tool log --token <string> [--profile|-p <string>] <command> [<file>]\n",
+		Commands:    map[string]string{"tail": "Follow logs\nuntil interrupted"},
+		Arguments: []*option.Option{
+			option.New("file", option.StringType, &file).SetDescription("Log file."),
+		},
+		Options: []*option.Option{
+			option.New("profile", option.StringType, &profile).SetAlias("p").SetDescription("Profile to use.").
+				SetDefaultStr(`"default"`).SetEnvVar("TOOL_PROFILE").SetValidValues("default", "dev"),
+			option.New("token", option.StringType, &token).SetRequired(""),
+		},
+		SeeAlso: []string{"tool", "tool log tail"},
+	}
+
+	tests := []struct {
+		name     string
+		format   DocFormat
+		expected string
+	}{
+		{"markdown", MarkdownFormat, "# tool log\n\n" +
+			"Show logs.\n\n" +
+			"## SYNOPSIS\n\n" +
+			"```\ntool log --token <string> [--profile|-p <string>] <command> [<file>]\n```\n\n" +
+			"## COMMANDS\n\n" +
+			"| Command | Description |\n" +
+			"| --- | --- |\n" +
+			"| [tail](tool-log-tail.md) | Follow logs<br>until interrupted |\n\n" +
+			"## ARGUMENTS\n\n" +
+			"| Argument | Type | Default | Required | Description |\n" +
+			"| --- | --- | --- | --- | --- |\n" +
+			"| `[<file>]` | `string` |  |  | Log file. |\n\n" +
+			"## OPTIONS\n\n" +
+			"| Option | Aliases | Type | Default | Env | Required | Description |\n" +
+			"| --- | --- | --- | --- | --- | --- | --- |\n" +
+			"| `--token` |  | `string` |  |  | yes |  |\n" +
+			"| `--profile` | `-p` | `string` | `\"default\"` | `TOOL_PROFILE` |  | Profile to use. (valid values: default\\|dev) |\n\n" +
+			"## SEE ALSO\n\n" +
+			"* [tool](tool.md)\n" +
+			"* [tool log tail](tool-log-tail.md)\n"},
+		{"asciidoc", AsciiDocFormat, "= tool log\n\n" +
+			"Show logs.\n\n" +
+			"== SYNOPSIS\n\n" +
+			"----\ntool log --token <string> [--profile|-p <string>] <command> [<file>]\n----\n\n" +
+			"== COMMANDS\n\n" +
+			"[options=\"header\"]\n|===\n|Command |Description\n\n" +
+			"|xref:tool-log-tail.adoc[tail]\n|Follow logs +\nuntil interrupted\n|===\n\n" +
+			"== ARGUMENTS\n\n" +
+			"[options=\"header\"]\n|===\n|Argument |Type |Default |Required |Description\n\n" +
+			"|`+[<file>]+`\n|`+string+`\n|\n|\n|Log file.\n|===\n\n" +
+			"== OPTIONS\n\n" +
+			"[options=\"header\"]\n|===\n|Option |Aliases |Type |Default |Env |Required |Description\n\n" +
+			"|`+--token+`\n|\n|`+string+`\n|\n|\n|yes\n|\n\n" +
+			"|`+--profile+`\n|`+-p+`\n|`+string+`\n|`+\"default\"+`\n|`+TOOL_PROFILE+`\n|\n|Profile to use. (valid values: default\\|dev)\n|===\n\n" +
+			"== SEE ALSO\n\n" +
+			"* xref:tool.adoc[tool]\n" +
+			"* xref:tool-log-tail.adoc[tool log tail]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Doc(page, tt.format)
+			if got != tt.expected {
+				t.Errorf("Unexpected document:\n%s", firstDiff(got, tt.expected))
+				t.Errorf("got:\n%s\nexpected:\n%s\n", got, tt.expected)
+			}
+		})
+	}
+
+	if DocFile("tool log", AsciiDocFormat) != "tool-log.adoc" {
+		t.Errorf("Unexpected file name: %s", DocFile("tool log", AsciiDocFormat))
+	}
+}
@@ -53,8 +53,48 @@ func Name(scriptName, name, description string) string {
 	return fmt.Sprintf("%s:\n%s\n", text.HelpNameHeader, indent(out))
 }
 
+// GroupKind - Indicates how the options in a Group relate to each other.
+type GroupKind int
+
+// Group kinds
+const (
+	MutuallyExclusiveGroup GroupKind = iota // [--json | --yaml]
+	RequiredTogetherGroup                   // [--user <string> --password <string>]
+	AtLeastOneOfGroup                       // (--json | --yaml)
+)
+
+// Group - Options that are shown together in the synopsis.
+type Group struct {
+	Kind    GroupKind
+	Options []*option.Option
+}
+
+// synopsis - Returns the synopsis representation of the group.
+func (g Group) synopsis() string {
+	list := []string{}
+	required := false
+	for _, opt := range g.Options {
+		syn := opt.HelpSynopsis
+		if opt.MaxArgs > 0 {
+			syn += "..."
+		}
+		list = append(list, syn)
+		required = required || opt.IsRequired
+	}
+	switch g.Kind {
+	case RequiredTogetherGroup:
+		return wrapFn(!required, "[", "]")(strings.Join(list, " "))
+	case AtLeastOneOfGroup:
+		return fmt.Sprintf("(%s)", strings.Join(list, " | "))
+	default: // MutuallyExclusiveGroup
+		return fmt.Sprintf("[%s]", strings.Join(list, " | "))
+	}
+}
+
 // Synopsis - Return a default synopsis.
-func Synopsis(scriptName, name, args string, options []*option.Option, commands []string) string {
+//
+// Options that are part of a group are shown together as part of the group.
+func Synopsis(scriptName, name, args string, options []*option.Option, commands []string, groups []Group) string {
 	synopsisName := scriptName
 	if scriptName != "" {
 		synopsisName += fmt.Sprintf(" %s", name)
@@ -65,9 +105,26 @@ func Synopsis(scriptName, name, args string, options []*option.Option, commands
 	if name != "" {
 		scriptName += " " + name
 	}
+	grouped := map[*option.Option]bool{}
+	groupSynopsis := []string{}
+	for _, group := range groups {
+		g := Group{Kind: group.Kind}
+		for _, opt := range group.Options {
+			if !grouped[opt] && !opt.IsHidden {
+				grouped[opt] = true
+				g.Options = append(g.Options, opt)
+			}
+		}
+		if len(g.Options) > 0 {
+			groupSynopsis = append(groupSynopsis, g.synopsis())
+		}
+	}
 	normalOptions := []*option.Option{}
 	requiredOptions := []*option.Option{}
 	for _, option := range options {
+		if grouped[option] || option.IsHidden {
+			continue
+		}
 		if option.IsRequired {
 			requiredOptions = append(requiredOptions, option)
 		} else {
@@ -80,9 +137,11 @@ func Synopsis(scriptName, name, args string, options []*option.Option, commands
 		txt := ""
 		wrap := wrapFn(!opt.IsRequired, "[", "]")
 		switch opt.OptType {
-		case option.BoolType, option.StringType, option.IntType, option.Float64Type:
+		case option.BoolType, option.StringType, option.IntType, option.Float64Type, option.ValueType,
+			option.Int64Type, option.UintType, option.Uint64Type, option.DurationType:
 			txt += wrap(opt.HelpSynopsis)
-		case option.StringRepeatType, option.IntRepeatType, option.StringMapType:
+		case option.StringRepeatType, option.IntRepeatType, option.StringMapType,
+			option.Int64RepeatType, option.UintRepeatType, option.Uint64RepeatType, option.DurationRepeatType:
 			if opt.IsRequired {
 				wrap = wrapFn(opt.IsRequired, "<", ">")
 			}
@@ -90,10 +149,17 @@ func Synopsis(scriptName, name, args string, options []*option.Option, commands
 		}
 		return txt
 	}
+	synList := []string{}
+	for _, option := range requiredOptions {
+		synList = append(synList, optSynopsis(option))
+	}
+	synList = append(synList, groupSynopsis...)
+	for _, option := range normalOptions {
+		synList = append(synList, optSynopsis(option))
+	}
 	var out string
 	line := synopsisName
-	for _, option := range append(requiredOptions, normalOptions...) {
-		syn := optSynopsis(option)
+	for _, syn := range synList {
 		// fmt.Printf("%d - %d - %d | %s | %s\n", len(line), len(syn), len(line)+len(syn), syn, line)
 		if len(line)+len(syn) > 80 {
 			out += line + "\n"
@@ -162,12 +228,41 @@ func pad(do bool, s string, factor int) string {
 	return s
 }
 
+// optionDetails - Returns the default, valid values and env var details shown next to the option description.
+func optionDetails(opt *option.Option) []string {
+	details := []string{}
+	if !opt.IsRequired {
+		details = append(details, fmt.Sprintf("default: %s", opt.DefaultStr))
+	}
+	if len(opt.ValidValues) > 0 {
+		details = append(details, fmt.Sprintf("valid values: %s", strings.Join(opt.ValidValues, "|")))
+	}
+	if opt.EnvVar != "" {
+		details = append(details, fmt.Sprintf("env: %s", opt.EnvVar))
+	}
+	if opt.IsDeprecated {
+		details = append(details, DeprecatedDetail(opt.DeprecatedMsg))
+	}
+	return details
+}
+
+// DeprecatedDetail - Returns the text that flags a deprecated option or command: "deprecated" or "deprecated: <msg>".
+func DeprecatedDetail(msg string) string {
+	if msg == "" {
+		return text.HelpDeprecated
+	}
+	return fmt.Sprintf("%s: %s", text.HelpDeprecated, msg)
+}
+
 // OptionList - Return a formatted list of options and their descriptions.
 func OptionList(options []*option.Option) string {
 	synopsisLength := 0
 	normalOptions := []*option.Option{}
 	requiredOptions := []*option.Option{}
 	for _, opt := range options {
+		if opt.IsHidden {
+			continue
+		}
 		l := len(opt.HelpSynopsis)
 		if l > synopsisLength {
 			synopsisLength = l
@@ -184,29 +279,19 @@ func OptionList(options []*option.Option) string {
 		txt := ""
 		factor := synopsisLength + 4
 		padding := strings.Repeat(" ", factor)
-		txt += indent(pad(!opt.IsRequired || opt.Description != "" || opt.EnvVar != "", opt.HelpSynopsis, factor))
+		details := optionDetails(opt)
+		txt += indent(pad(opt.Description != "" || len(details) > 0, opt.HelpSynopsis, factor))
 		if opt.Description != "" {
 			description := strings.ReplaceAll(opt.Description, "\n", "\n    "+padding)
 			txt += description
 		}
-		if !opt.IsRequired {
+		if len(details) > 0 {
 			if opt.Description != "" {
 				txt += " "
 			}
-			txt += fmt.Sprintf("(default: %s", opt.DefaultStr)
-			if opt.EnvVar != "" {
-				txt += fmt.Sprintf(", env: %s", opt.EnvVar)
-			}
-			txt += ")\n\n"
-		} else {
-			if opt.EnvVar != "" {
-				if opt.Description != "" {
-					txt += " "
-				}
-				txt += fmt.Sprintf("(env: %s)", opt.EnvVar)
-			}
-			txt += "\n\n"
+			txt += fmt.Sprintf("(%s)", strings.Join(details, ", "))
 		}
+		txt += "\n\n"
 		return txt
 	}
 	out := ""
@@ -224,3 +309,67 @@ func OptionList(options []*option.Option) string {
 	}
 	return out
 }
+
+// argumentSynopsis - Returns the synopsis representation of a positional argument: <file>, [<file>] or <file>...
+func argumentSynopsis(arg *option.Option) string {
+	syn := fmt.Sprintf("<%s>", arg.Name)
+	switch arg.OptType {
+	case option.StringRepeatType, option.IntRepeatType:
+		syn += "..."
+	}
+	return wrapFn(!arg.IsRequired, "[", "]")(syn)
+}
+
+// ArgumentsSynopsis - Returns the synopsis representation of the positional arguments.
+func ArgumentsSynopsis(arguments []*option.Option) string {
+	list := []string{}
+	for _, arg := range arguments {
+		list = append(list, argumentSynopsis(arg))
+	}
+	return strings.Join(list, " ")
+}
+
+// argumentDetails - Returns the default and valid values details shown next to the argument description.
+func argumentDetails(arg *option.Option) []string {
+	details := []string{}
+	if !arg.IsRequired && arg.DefaultStr != "" && arg.DefaultStr != "[]" {
+		details = append(details, fmt.Sprintf("default: %s", arg.DefaultStr))
+	}
+	if len(arg.ValidValues) > 0 {
+		details = append(details, fmt.Sprintf("valid values: %s", strings.Join(arg.ValidValues, "|")))
+	}
+	return details
+}
+
+// ArgumentList - Return a formatted list of positional arguments and their descriptions.
+// Arguments are listed in definition order.
+func ArgumentList(arguments []*option.Option) string {
+	if len(arguments) == 0 {
+		return ""
+	}
+	synopsisLength := 0
+	for _, arg := range arguments {
+		l := len(argumentSynopsis(arg))
+		if l > synopsisLength {
+			synopsisLength = l
+		}
+	}
+	out := ""
+	for _, arg := range arguments {
+		factor := synopsisLength + 4
+		padding := strings.Repeat(" ", factor)
+		details := argumentDetails(arg)
+		txt := indent(pad(arg.Description != "" || len(details) > 0, argumentSynopsis(arg), factor))
+		if arg.Description != "" {
+			txt += strings.ReplaceAll(arg.Description, "\n", "\n    "+padding)
+		}
+		if len(details) > 0 {
+			if arg.Description != "" {
+				txt += " "
+			}
+			txt += fmt.Sprintf("(%s)", strings.Join(details, ", "))
+		}
+		out += txt + "\n"
+	}
+	return fmt.Sprintf("%s:\n%s", text.HelpArgumentsHeader, out)
+}
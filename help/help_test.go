@@ -55,22 +55,22 @@ func TestHelp(t *testing.T) {
         description
         that is very long
 `},
-		{"Synopsis", Synopsis("", scriptName, "", nil, []string{}), `SYNOPSIS:
+		{"Synopsis", Synopsis("", scriptName, "", nil, []string{}, nil), `SYNOPSIS:
     help.test [<args>]
 `},
-		{"Synopsis", Synopsis(scriptName, "log", "", nil, []string{}), `SYNOPSIS:
+		{"Synopsis", Synopsis(scriptName, "log", "", nil, []string{}, nil), `SYNOPSIS:
     help.test log [<args>]
 `},
-		{"Synopsis", Synopsis(scriptName, "log", "<filename>", nil, []string{}), `SYNOPSIS:
+		{"Synopsis", Synopsis(scriptName, "log", "<filename>", nil, []string{}, nil), `SYNOPSIS:
     help.test log <filename>
 `},
 		{"Synopsis", Synopsis(scriptName, "log", "",
-			[]*option.Option{func() *option.Option { b := false; return option.New("bool", option.BoolType, &b) }()}, []string{}),
+			[]*option.Option{func() *option.Option { b := false; return option.New("bool", option.BoolType, &b) }()}, []string{}, nil),
 			`SYNOPSIS:
     help.test log [--bool] [<args>]
 `},
 		{"Synopsis", Synopsis(scriptName, "log", "",
-			[]*option.Option{boolOpt()}, []string{}),
+			[]*option.Option{boolOpt()}, []string{}, nil),
 			`SYNOPSIS:
     help.test log [--bool|-b] [<args>]
 `},
@@ -82,7 +82,7 @@ func TestHelp(t *testing.T) {
 				ssOpt(),
 				iiOpt(),
 				mOpt(),
-			}, []string{}),
+			}, []string{}, nil),
 			`SYNOPSIS:
     help.test log [--bool|-b] [--float <float64>] [--ii <int>]... [--int <int>]
                   [-m <key=value>]... [--ss <string>]... [<args>]
@@ -95,7 +95,7 @@ func TestHelp(t *testing.T) {
 				ssOpt().SetRequired(""),
 				iiOpt().SetRequired(""),
 				mOpt().SetRequired(""),
-			}, []string{}),
+			}, []string{}, nil),
 			`SYNOPSIS:
     help.test log --bool|-b --float <float64> <--ii <int>>... --int <int>
                   <-m <key=value>>... <--ss <string>>... [<args>]
@@ -108,7 +108,7 @@ func TestHelp(t *testing.T) {
 				ssOpt().SetRequired(""),
 				iiOpt().SetRequired(""),
 				mOpt().SetRequired(""),
-			}, []string{"log", "show"}),
+			}, []string{"log", "show"}, nil),
 			`SYNOPSIS:
     help.test log --bool|-b --float <float64> <--ii <int>>... --int <int>
                   <-m <key=value>>... <--ss <string>>... <command> [<args>]
@@ -122,11 +122,35 @@ func TestHelp(t *testing.T) {
 				iiOpt().SetRequired(""),
 				mOpt().SetRequired(""),
 				func() *option.Option { m := map[string]string{}; return option.New("z", option.StringMapType, &m) }().SetRequired(""),
-			}, []string{"log", "show"}),
+			}, []string{"log", "show"}, nil),
 			`SYNOPSIS:
     help.test log --bool|-b --float <float64> <--ii <int>>... --int <int>
                   <-m <key=value>>... <--ss <string>>... <-z <key=value>>...
                   <command> [<args>]
+`},
+		{"Synopsis groups", func() string {
+			b, i, f, ss := boolOpt(), intOpt(), floatOpt(), ssOpt()
+			ss.MaxArgs = 1
+			return Synopsis(scriptName, "log", "",
+				[]*option.Option{b, i, f, ss, mOpt()}, []string{},
+				[]Group{
+					{Kind: MutuallyExclusiveGroup, Options: []*option.Option{b, ss}},
+					{Kind: RequiredTogetherGroup, Options: []*option.Option{i, f}},
+					{Kind: AtLeastOneOfGroup, Options: []*option.Option{b, f}},
+				})
+		}(),
+			`SYNOPSIS:
+    help.test log [--bool|-b | --ss <string>...] [--int <int> --float <float64>]
+                  [-m <key=value>]... [<args>]
+`},
+		{"Synopsis required group", func() string {
+			i, f := intOpt().SetRequired(""), floatOpt().SetRequired("")
+			return Synopsis(scriptName, "log", "",
+				[]*option.Option{i, f}, []string{},
+				[]Group{{Kind: RequiredTogetherGroup, Options: []*option.Option{i, f}}})
+		}(),
+			`SYNOPSIS:
+    help.test log --int <int> --float <float64> [<args>]
 `},
 		{"OptionList nil", OptionList(nil), ""},
 		{"OptionList empty", OptionList([]*option.Option{}), ""},
@@ -246,6 +270,57 @@ OPTIONS:
 
     --string-repeat <my_value>    string repeat (default: [], env: STRING_REPEAT)
 
+`},
+		{"OptionList valid values", OptionList([]*option.Option{
+			func() *option.Option {
+				s := ""
+				return option.New("format", option.StringType, &s)
+			}().SetDefaultStr(`"json"`).SetValidValues("json", "yaml").SetDescription("output format"),
+			func() *option.Option {
+				s := ""
+				return option.New("color", option.StringType, &s)
+			}().SetValidValues("always", "never").SetRequired("").SetEnvVar("COLOR"),
+		}), `REQUIRED PARAMETERS:
+    --color <string>     (valid values: always|never, env: COLOR)
+
+OPTIONS:
+    --format <string>    output format (default: "json", valid values: json|yaml)
+
+`},
+		{"OptionList required deprecated", OptionList([]*option.Option{
+			boolOpt().SetRequired(""),
+			intOpt().SetRequired("").SetDeprecated("use --bool"),
+		}), `REQUIRED PARAMETERS:
+    --bool|-b
+
+    --int <int>    (deprecated: use --bool)
+
+`},
+		{"Synopsis arguments", Synopsis("", scriptName, ArgumentsSynopsis([]*option.Option{
+			func() *option.Option { s := ""; return option.New("src", option.StringType, &s) }().SetRequired(""),
+			func() *option.Option { i := 0; return option.New("count", option.IntType, &i) }(),
+			ssOpt(),
+		}), []*option.Option{boolOpt()}, []string{}, nil), `SYNOPSIS:
+    help.test [--bool|-b] <src> [<count>] [<ss>...]
+`},
+		{"ArgumentList", ArgumentList(nil), ""},
+		{"ArgumentList", ArgumentList([]*option.Option{
+			func() *option.Option {
+				s := ""
+				return option.New("src", option.StringType, &s)
+			}().SetRequired("").SetDescription("source file"),
+			func() *option.Option { i := 0; return option.New("count", option.IntType, &i) }().SetDefaultStr("1").SetDescription("count\nmultiline"),
+			func() *option.Option {
+				s := ""
+				return option.New("format", option.StringType, &s)
+			}().SetValidValues("json", "yaml"),
+			iiOpt().SetDefaultStr("[]").SetRequired(""),
+		}), `ARGUMENTS:
+    <src>         source file
+    [<count>]     count
+                  multiline (default: 1)
+    [<format>]    (valid values: json|yaml)
+    <ii>...
 `},
 		{"CommandList", CommandList(nil), ""},
 		{"CommandList", CommandList(map[string]string{}), ""},
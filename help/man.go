@@ -0,0 +1,181 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package help
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zhizh/go-getoptions/option"
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// ManPage - Contents of a roff man page or reference document for a program or command.
+type ManPage struct {
+	Name        string            // Program or command name, for example: "mygit log"
+	Section     int               // Manual section, usually 1 for user commands
+	Description string            // The first line is used in the NAME section, the full text in the DESCRIPTION section
+	Synopsis    string            // Output of Synopsis
+	Commands    map[string]string // name: description
+	Arguments   []*option.Option
+	Options     []*option.Option
+	SeeAlso     []string // Names of related pages, for example: "mygit"
+}
+
+// ManPageName - Returns the page name for the program or command: "mygit log" -> "mygit-log".
+func ManPageName(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
+
+// sortedOptions - Returns the required options followed by the other options, each sorted by name, as in OptionList.
+func (page ManPage) sortedOptions() ([]*option.Option, []*option.Option) {
+	normalOptions := []*option.Option{}
+	requiredOptions := []*option.Option{}
+	for _, opt := range page.Options {
+		if opt.IsHidden {
+			continue
+		}
+		if opt.IsRequired {
+			requiredOptions = append(requiredOptions, opt)
+		} else {
+			normalOptions = append(normalOptions, opt)
+		}
+	}
+	option.Sort(normalOptions)
+	option.Sort(requiredOptions)
+	return requiredOptions, normalOptions
+}
+
+// roffEscape - Escapes the characters with special meaning in roff.
+// Lines that would be read as requests are prefixed with a zero width character.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		switch {
+		case line == "":
+			lines[i] = ".sp"
+		case strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'"):
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// roffBold - Returns the escaped string in bold.
+func roffBold(s string) string {
+	return `\fB` + roffEscape(s) + `\fR`
+}
+
+// manSection - Returns the section header.
+func manSection(header string) string {
+	return fmt.Sprintf(".SH %s\n", strings.ToUpper(header))
+}
+
+// manEntry - Returns a tagged paragraph with the description and its details in parenthesis.
+func manEntry(tag, description string, details []string) string {
+	out := fmt.Sprintf(".TP\n%s\n", tag)
+	if description != "" {
+		out += roffEscape(description) + "\n"
+	}
+	if len(details) > 0 {
+		out += roffEscape(fmt.Sprintf("(%s)", strings.Join(details, ", "))) + "\n"
+	}
+	return out
+}
+
+// Man - Return the roff man page.
+//
+// The page follows the NAME, SYNOPSIS, COMMANDS, ARGUMENTS and OPTIONS structure of the help output,
+// adding DESCRIPTION, ENVIRONMENT and SEE ALSO sections when there is content for them.
+func Man(page ManPage) string {
+	name := ManPageName(page.Name)
+	out := fmt.Sprintf(".TH \"%s\" \"%d\"\n", roffEscape(strings.ToUpper(name)), page.Section)
+
+	out += manSection(text.HelpNameHeader)
+	description := strings.TrimSpace(page.Description)
+	lines := strings.SplitN(description, "\n", 2)
+	if lines[0] != "" {
+		out += roffEscape(fmt.Sprintf("%s - %s", name, lines[0])) + "\n"
+	} else {
+		out += roffEscape(name) + "\n"
+	}
+
+	if page.Synopsis != "" {
+		out += manSection(text.HelpSynopsisHeader)
+		out += ".nf\n"
+		for _, line := range synopsisLines(page.Synopsis) {
+			out += roffEscape(line) + "\n"
+		}
+		out += ".fi\n"
+	}
+
+	if len(lines) > 1 {
+		out += manSection(text.HelpDescriptionHeader)
+		out += roffEscape(description) + "\n"
+	}
+
+	if len(page.Commands) > 0 {
+		out += manSection(text.HelpCommandsHeader)
+		names := []string{}
+		for name := range page.Commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, command := range names {
+			out += manEntry(roffBold(command), page.Commands[command], nil)
+		}
+	}
+
+	if len(page.Arguments) > 0 {
+		out += manSection(text.HelpArgumentsHeader)
+		for _, arg := range page.Arguments {
+			out += manEntry(roffBold(argumentSynopsis(arg)), arg.Description, argumentDetails(arg))
+		}
+	}
+
+	requiredOptions, normalOptions := page.sortedOptions()
+	if len(requiredOptions) > 0 {
+		out += manSection(text.HelpRequiredOptionsHeader)
+		for _, opt := range requiredOptions {
+			out += manEntry(roffBold(opt.HelpSynopsis), opt.Description, optionDetails(opt))
+		}
+	}
+	if len(normalOptions) > 0 {
+		out += manSection(text.HelpOptionsHeader)
+		for _, opt := range normalOptions {
+			out += manEntry(roffBold(opt.HelpSynopsis), opt.Description, optionDetails(opt))
+		}
+	}
+
+	envOptions := []*option.Option{}
+	for _, opt := range page.Options {
+		if opt.EnvVar != "" && !opt.IsHidden {
+			envOptions = append(envOptions, opt)
+		}
+	}
+	if len(envOptions) > 0 {
+		sort.Slice(envOptions, func(i, j int) bool { return envOptions[i].EnvVar < envOptions[j].EnvVar })
+		out += manSection(text.HelpEnvironmentHeader)
+		for _, opt := range envOptions {
+			out += fmt.Sprintf(".TP\n%s\n%s\n", roffBold(opt.EnvVar), fmt.Sprintf(text.HelpEnvVarDescription, roffBold(option.Dashed(opt.Name))))
+		}
+	}
+
+	if len(page.SeeAlso) > 0 {
+		out += manSection(text.HelpSeeAlsoHeader)
+		refs := []string{}
+		for _, ref := range page.SeeAlso {
+			refs = append(refs, fmt.Sprintf("%s(%d)", roffBold(ManPageName(ref)), page.Section))
+		}
+		out += strings.Join(refs, ",\n") + "\n"
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package help
+
+import (
+	"testing"
+
+	"github.com/zhizh/go-getoptions/option"
+)
+
+func TestMan(t *testing.T) {
+	debug := false
+	profile := "default"
+	token := ""
+	file := ""
+
+	options := []*option.Option{
+		option.New("debug", option.BoolType, &debug).SetDescription("Show debug output.").SetDefaultStr("false"),
+		option.New("profile", option.StringType, &profile).SetAlias("p").SetHelpArgName("name").
+			SetDescription("Profile to use.\n.profile files are read from $HOME").SetDefaultStr(`"default"`).SetEnvVar("TOOL_PROFILE"),
+		option.New("token", option.StringType, &token).SetRequired("").SetEnvVar("TOOL_TOKEN"),
+	}
+	arguments := []*option.Option{
+		option.New("file", option.StringType, &file).SetDescription(`Input file, \ is the separator.`),
+	}
+
+	tests := []struct {
+		name     string
+		page     ManPage
+		expected string
+	}{
+		{"empty", ManPage{Name: "tool", Section: 1}, `.TH "TOOL" "1"
+.SH NAME
+tool
+`},
+		{"command", ManPage{
+			Name:        "tool log",
+			Section:     8,
+			Description: "Show logs.\n\nLogs are read from the server.",
+			Synopsis:    Synopsis("tool", "log", "", options, []string{"tail"}, nil),
+			Commands:    map[string]string{"tail": "Follow logs", "show": ""},
+			Arguments:   arguments,
+			Options:     options,
+			SeeAlso:     []string{"tool", "tool log show", "tool log tail"},
+		}, `.TH "TOOL\-LOG" "8"
+.SH NAME
+tool\-log \- Show logs.
+.SH SYNOPSIS
+.nf
+tool log \-\-token <string> [\-\-debug] [\-\-profile|\-p <name>] <command> [<args>]
+.fi
+.SH DESCRIPTION
+Show logs.
+.sp
+Logs are read from the server.
+.SH COMMANDS
+.TP
+\fBshow\fR
+.TP
+\fBtail\fR
+Follow logs
+.SH ARGUMENTS
+.TP
+\fB[<file>]\fR
+Input file, \e is the separator.
+.SH REQUIRED PARAMETERS
+.TP
+\fB\-\-token <string>\fR
+(env: TOOL_TOKEN)
+.SH OPTIONS
+.TP
+\fB\-\-debug\fR
+Show debug output.
+(default: false)
+.TP
+\fB\-\-profile|\-p <name>\fR
+Profile to use.
+\&.profile files are read from $HOME
+(default: "default", env: TOOL_PROFILE)
+.SH ENVIRONMENT
+.TP
+\fBTOOL_PROFILE\fR
+Sets the value of \fB\-\-profile\fR.
+.TP
+\fBTOOL_TOKEN\fR
+Sets the value of \fB\-\-token\fR.
+.SH SEE ALSO
+\fBtool\fR(8),
+\fBtool\-log\-show\fR(8),
+\fBtool\-log\-tail\fR(8)
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Man(tt.page)
+			if got != tt.expected {
+				t.Errorf("Unexpected man page:\n%s", firstDiff(got, tt.expected))
+				t.Errorf("got:\n%s\nexpected:\n%s\n", got, tt.expected)
+			}
+		})
+	}
+}
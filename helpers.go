@@ -10,6 +10,7 @@ package getoptions
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -53,3 +54,69 @@ func isOption(s string, mode Mode) (options []string, argument string) {
 	}
 	return []string{}, ""
 }
+
+// editDistance - Returns the optimal string alignment distance between two strings.
+// Same as the Levenshtein distance but a transposition of two adjacent characters counts as a single edit: shwo -> show.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// suggestions - Returns the candidates that are close to the given name, closest first.
+// Short names only allow a distance of 1 and single letter names are ignored to avoid unrelated suggestions.
+func suggestions(name string, candidates []string) []string {
+	maxDistance := 2
+	if len(name) <= 3 {
+		maxDistance = 1
+	}
+	distance := map[string]int{}
+	for _, c := range candidates {
+		if len(name) <= 1 || len(c) <= 1 {
+			continue
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(c)); d <= maxDistance {
+			distance[c] = d
+		}
+	}
+	list := []string{}
+	for c := range distance {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if distance[list[i]] != distance[list[j]] {
+			return distance[list[i]] < distance[list[j]]
+		}
+		return list[i] < list[j]
+	})
+	return list
+}
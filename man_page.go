@@ -0,0 +1,87 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zhizh/go-getoptions/help"
+)
+
+// ManPage - Returns the roff man page for the program or command in the given manual section.
+//
+// The page has the same NAME, SYNOPSIS, COMMANDS, ARGUMENTS and OPTIONS sections as the help output,
+// including option defaults and environment variables, and references the parent and child command pages.
+// For example, to view it:
+//
+//     fmt.Print(opt.ManPage(1)) // | man -l -
+func (gopt *GetOpt) ManPage(section int) string {
+	gopt.root().passOptionsToChildren()
+	return help.Man(gopt.page(section))
+}
+
+// ManPages - Returns the roff man pages for the program and all its commands in the given manual section.
+// The pages are indexed by file name, for example: "mygit.1" and "mygit-log.1".
+func (gopt *GetOpt) ManPages(section int) map[string]string {
+	gopt.root().passOptionsToChildren()
+	pages := map[string]string{}
+	gopt.walkCommands(func(opt *GetOpt) {
+		pages[fmt.Sprintf("%s.%d", help.ManPageName(getCommandName(opt)), section)] = help.Man(opt.page(section))
+	})
+	return pages
+}
+
+// root - Returns the top level GetOpt object.
+func (gopt *GetOpt) root() *GetOpt {
+	root := gopt
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+// walkCommands - Calls fn for the GetOpt object and all its commands, recursively.
+// Hidden commands are skipped.
+func (gopt *GetOpt) walkCommands(fn func(opt *GetOpt)) {
+	fn(gopt)
+	for _, command := range gopt.commands {
+		if !command.hidden {
+			command.walkCommands(fn)
+		}
+	}
+}
+
+// page - Returns the help page contents of the program or command.
+func (gopt *GetOpt) page(section int) help.ManPage {
+	page := help.ManPage{
+		Name:        getCommandName(gopt),
+		Section:     section,
+		Description: gopt.description,
+		Synopsis:    gopt.Help(HelpSynopsis),
+		Commands:    map[string]string{},
+		Arguments:   gopt.arguments,
+	}
+	for _, opt := range gopt.obj {
+		page.Options = append(page.Options, opt)
+	}
+	children := []string{}
+	if gopt.parent != nil {
+		page.SeeAlso = append(page.SeeAlso, getCommandName(gopt.parent))
+	}
+	for _, command := range gopt.commands {
+		if !command.hidden {
+			page.Commands[command.name] = command.commandListDescription()
+			children = append(children, getCommandName(command))
+		}
+	}
+	sort.Strings(children)
+	page.SeeAlso = append(page.SeeAlso, children...)
+	return page
+}
@@ -0,0 +1,65 @@
+package getoptions
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestManPages(t *testing.T) {
+	opt := New()
+	opt.name = "tool"
+	opt.Self("", "A tool.")
+	opt.String("profile", "default", opt.Alias("p"), opt.GetEnv("TOOL_PROFILE"), opt.Description("Profile to use."))
+	log := opt.NewCommand("log", "Show logs")
+	log.Int("lines", 10, opt.Description("Number of lines."))
+	log.NewCommand("tail", "Follow logs")
+
+	pages := opt.ManPages(1)
+	names := []string{}
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, " ") != "tool-log-tail.1 tool-log.1 tool.1" {
+		t.Errorf("Unexpected pages: %v\n", names)
+	}
+
+	expected := `.TH "TOOL\-LOG" "1"
+.SH NAME
+tool\-log \- Show logs
+.SH SYNOPSIS
+.nf
+tool log [\-\-lines <int>] [\-\-profile|\-p <string>] <command> [<args>]
+.fi
+.SH COMMANDS
+.TP
+\fBtail\fR
+Follow logs
+.SH OPTIONS
+.TP
+\fB\-\-lines <int>\fR
+Number of lines.
+(default: 10)
+.TP
+\fB\-\-profile|\-p <string>\fR
+Profile to use.
+(default: "default", env: TOOL_PROFILE)
+.SH ENVIRONMENT
+.TP
+\fBTOOL_PROFILE\fR
+Sets the value of \fB\-\-profile\fR.
+.SH SEE ALSO
+\fBtool\fR(1),
+\fBtool\-log\-tail\fR(1)
+`
+	if pages["tool-log.1"] != expected {
+		t.Errorf("Unexpected page:\n%s", firstDiff(pages["tool-log.1"], expected))
+	}
+	if log.ManPage(1) != expected {
+		t.Errorf("Unexpected page:\n%s", firstDiff(log.ManPage(1), expected))
+	}
+	if !strings.Contains(pages["tool.1"], "tool \\- A tool.\n") {
+		t.Errorf("Unexpected page:\n%s", pages["tool.1"])
+	}
+}
@@ -0,0 +1,102 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package option
+
+import (
+	"fmt"
+
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// ConversionError - Error returned when the argument can't be converted to the option type.
+type ConversionError struct {
+	Name     string // Option name
+	Alias    string // Alias, env var or config key used to set the option
+	Argument string // Argument that couldn't be converted
+	Type     string // bool, int, int64, uint, uint64, float64, duration or the Type() of a user defined Value
+	IsValue  bool   // Indicates if the option is a user defined Value, its error is part of the message
+	Err      error  // Underlying conversion error
+}
+
+func (e *ConversionError) Error() string {
+	if e.IsValue {
+		return fmt.Sprintf(text.ErrorConvertToValue, e.Alias, e.Type, e.Argument, e.Err)
+	}
+	switch e.Type {
+	case "bool":
+		return fmt.Sprintf(text.ErrorConvertToBool, e.Alias, e.Argument)
+	case "int":
+		return fmt.Sprintf(text.ErrorConvertToInt, e.Alias, e.Argument)
+	case "int64":
+		return fmt.Sprintf(text.ErrorConvertToInt64, e.Alias, e.Argument)
+	case "uint":
+		return fmt.Sprintf(text.ErrorConvertToUint, e.Alias, e.Argument)
+	case "uint64":
+		return fmt.Sprintf(text.ErrorConvertToUint64, e.Alias, e.Argument)
+	case "float64":
+		return fmt.Sprintf(text.ErrorConvertToFloat64, e.Alias, e.Argument)
+	case "duration":
+		return fmt.Sprintf(text.ErrorConvertToDuration, e.Alias, e.Argument)
+	}
+	return fmt.Sprintf(text.ErrorConvertToValue, e.Alias, e.Type, e.Argument, e.Err)
+}
+
+// Unwrap - Returns the underlying conversion error.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+func (opt *Option) conversionError(optType, argument string, err error) error {
+	return &ConversionError{Name: opt.Name, Alias: opt.UsedAlias, Argument: argument, Type: optType, IsValue: opt.OptType == ValueType, Err: err}
+}
+
+// InvalidValueError - Error returned when the argument is not one of the option valid values.
+type InvalidValueError struct {
+	Name        string   // Option name
+	Alias       string   // Alias, env var or config key used to set the option
+	Argument    string   // Argument that is not valid
+	ValidValues []string // Valid values set with opt.ValidValues
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf(text.ErrorInvalidValue, e.Alias, e.Argument, e.ValidValues)
+}
+
+// ValidationError - Error returned when the option value doesn't pass its validation function.
+type ValidationError struct {
+	Name  string // Option name
+	Alias string // Alias, env var or config key used to set the option
+	Err   error  // Error returned by the validation function
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(text.ErrorValidation, e.Alias, e.Err)
+}
+
+// Unwrap - Returns the error returned by the validation function.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// MissingRequiredError - Error returned when a required option or argument wasn't provided.
+type MissingRequiredError struct {
+	Name       string // Option or argument name
+	Msg        string // Custom error message set with opt.Required(msg)
+	IsArgument bool   // Indicates if the missing element is a positional argument
+}
+
+func (e *MissingRequiredError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	if e.IsArgument {
+		return fmt.Sprintf(text.ErrorMissingRequiredArgument, e.Name)
+	}
+	return fmt.Sprintf(text.ErrorMissingRequiredOption, e.Name)
+}
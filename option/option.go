@@ -10,12 +10,14 @@
 package option
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zhizh/go-getoptions/text"
 )
@@ -39,13 +41,73 @@ const (
 	StringRepeatType
 	IntRepeatType
 	StringMapType
+	ValueType
+	Int64Type
+	UintType
+	Uint64Type
+	DurationType
+	Int64RepeatType
+	UintRepeatType
+	Uint64RepeatType
+	DurationRepeatType
 )
 
+// String - Returns the Go type name of the option value, for example: "[]string".
+func (t Type) String() string {
+	switch t {
+	case BoolType:
+		return "bool"
+	case StringType:
+		return "string"
+	case IntType:
+		return "int"
+	case Float64Type:
+		return "float64"
+	case StringRepeatType:
+		return "[]string"
+	case IntRepeatType:
+		return "[]int"
+	case StringMapType:
+		return "map[string]string"
+	case ValueType:
+		return "value"
+	case Int64Type:
+		return "int64"
+	case UintType:
+		return "uint"
+	case Uint64Type:
+		return "uint64"
+	case DurationType:
+		return "duration"
+	case Int64RepeatType:
+		return "[]int64"
+	case UintRepeatType:
+		return "[]uint"
+	case Uint64RepeatType:
+		return "[]uint64"
+	case DurationRepeatType:
+		return "[]duration"
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// Value - Interface implemented by user defined option types.
+//
+// Set is called with the argument passed to the option, String returns the
+// current value (used as the default in the help) and Type returns the name of
+// the type (used as the argument name in the help).
+type Value interface {
+	Set(string) error
+	String() string
+	Type() string
+}
+
 // Option - main object
 type Option struct {
 	Name           string
 	Aliases        []string
 	EnvVar         string  // Env Var that sets the option value
+	ConfigKey      string  // Config file key that sets the option value
 	Called         bool    // Indicates if the option was passed on the command line
 	UsedAlias      string  // Alias/Env var used when the option was called
 	Handler        Handler // method used to handle the option
@@ -58,6 +120,19 @@ type Option struct {
 	IsRequired    bool   // Indicates if the option is required
 	IsRequiredErr string // Error message for the required option
 
+	IsHidden      bool   // Indicates if the option is excluded from the help and completion
+	IsDeprecated  bool   // Indicates if the option is deprecated
+	DeprecatedMsg string // Optional deprecation message, for example the option to use instead
+
+	ValidValues []string                // Optional list of accepted arguments
+	ValidateFn  func(interface{}) error // Optional function to validate the option value after it is saved
+
+	FileCompletion bool                                              // Indicates if the option argument is completed with file names
+	FilePatterns   []string                                          // Optional glob patterns that restrict the completed file names
+	DirCompletion  bool                                              // Indicates if the option argument is completed with directory names
+	IgnoreCase     bool                                              // Indicates if file and directory names are completed ignoring case
+	CompletionFn   func(ctx context.Context, prefix string) []string // Optional function that returns the completions for the option argument
+
 	// Help
 	DefaultStr   string // String representation of default value
 	Description  string // Optional description used for help
@@ -74,6 +149,16 @@ type Option struct {
 	pStringS *[]string          // receiver for string slice pointer
 	pIntS    *[]int             // receiver for int slice pointer
 	pStringM *map[string]string // receiver for string map pointer
+	pValue   Value              // receiver for user defined types
+
+	pInt64     *int64           // receiver for int64 pointer
+	pUint      *uint            // receiver for uint pointer
+	pUint64    *uint64          // receiver for uint64 pointer
+	pDuration  *time.Duration   // receiver for time.Duration pointer
+	pInt64S    *[]int64         // receiver for int64 slice pointer
+	pUintS     *[]uint          // receiver for uint slice pointer
+	pUint64S   *[]uint64        // receiver for uint64 slice pointer
+	pDurationS *[]time.Duration // receiver for time.Duration slice pointer
 
 	Unknown bool // Temporary marker used during parsing
 }
@@ -105,6 +190,33 @@ func New(name string, optType Type, data interface{}) *Option {
 	case StringMapType:
 		opt.HelpArgName = "key=value"
 		opt.pStringM = data.(*map[string]string)
+	case ValueType:
+		opt.pValue = data.(Value)
+		opt.HelpArgName = opt.pValue.Type()
+	case Int64Type:
+		opt.HelpArgName = "int64"
+		opt.pInt64 = data.(*int64)
+	case Int64RepeatType:
+		opt.HelpArgName = "int64"
+		opt.pInt64S = data.(*[]int64)
+	case UintType:
+		opt.HelpArgName = "uint"
+		opt.pUint = data.(*uint)
+	case UintRepeatType:
+		opt.HelpArgName = "uint"
+		opt.pUintS = data.(*[]uint)
+	case Uint64Type:
+		opt.HelpArgName = "uint64"
+		opt.pUint64 = data.(*uint64)
+	case Uint64RepeatType:
+		opt.HelpArgName = "uint64"
+		opt.pUint64S = data.(*[]uint64)
+	case DurationType:
+		opt.HelpArgName = "duration"
+		opt.pDuration = data.(*time.Duration)
+	case DurationRepeatType:
+		opt.HelpArgName = "duration"
+		opt.pDurationS = data.(*[]time.Duration)
 	case BoolType:
 		opt.pBool = data.(*bool)
 		opt.boolDefault = *data.(*bool)
@@ -113,15 +225,18 @@ func New(name string, optType Type, data interface{}) *Option {
 	return opt
 }
 
+// Dashed - Returns the alias as it is typed on the command line: -a or --alias.
+func Dashed(alias string) string {
+	if len(alias) == 1 {
+		return "-" + alias
+	}
+	return "--" + alias
+}
+
 func (opt *Option) synopsis() {
 	aliases := []string{}
 	for _, e := range opt.Aliases {
-		if len(e) > 1 {
-			e = "--" + e
-		} else {
-			e = "-" + e
-		}
-		aliases = append(aliases, e)
+		aliases = append(aliases, Dashed(e))
 	}
 	opt.HelpSynopsis = strings.Join(aliases, "|")
 	if opt.OptType != BoolType {
@@ -147,6 +262,24 @@ func (opt *Option) Value() interface{} {
 		return *opt.pFloat64
 	case StringMapType:
 		return *opt.pStringM
+	case ValueType:
+		return opt.pValue
+	case Int64Type:
+		return *opt.pInt64
+	case Int64RepeatType:
+		return *opt.pInt64S
+	case UintType:
+		return *opt.pUint
+	case UintRepeatType:
+		return *opt.pUintS
+	case Uint64Type:
+		return *opt.pUint64
+	case Uint64RepeatType:
+		return *opt.pUint64S
+	case DurationType:
+		return *opt.pDuration
+	case DurationRepeatType:
+		return *opt.pDurationS
 	default: // BoolType:
 		return *opt.pBool
 	}
@@ -185,20 +318,90 @@ func (opt *Option) SetRequired(msg string) *Option {
 	return opt
 }
 
+// SetHidden - Excludes the option from the help and completion.
+func (opt *Option) SetHidden() *Option {
+	opt.IsHidden = true
+	return opt
+}
+
+// SetDeprecated - Marks an option as deprecated.
+func (opt *Option) SetDeprecated(msg string) *Option {
+	opt.IsDeprecated = true
+	opt.DeprecatedMsg = msg
+	return opt
+}
+
+// SetValidValues - Restricts the arguments accepted by the option to the given list.
+func (opt *Option) SetValidValues(values ...string) *Option {
+	opt.ValidValues = values
+	return opt
+}
+
+// SetFileCompletion - Indicates that the option argument is completed with file names.
+func (opt *Option) SetFileCompletion(b bool) *Option {
+	opt.FileCompletion = b
+	return opt
+}
+
+// SetFilePatterns - Restricts the completed file names to the ones matching the glob patterns.
+func (opt *Option) SetFilePatterns(patterns ...string) *Option {
+	opt.FilePatterns = patterns
+	return opt
+}
+
+// SetDirCompletion - Indicates that the option argument is completed with directory names.
+func (opt *Option) SetDirCompletion(b bool) *Option {
+	opt.DirCompletion = b
+	return opt
+}
+
+// SetIgnoreCase - Indicates that file and directory names are completed ignoring case.
+func (opt *Option) SetIgnoreCase(b bool) *Option {
+	opt.IgnoreCase = b
+	return opt
+}
+
+// SetCompletionFn - Sets the function that returns the completions for the option argument.
+func (opt *Option) SetCompletionFn(fn func(ctx context.Context, prefix string) []string) *Option {
+	opt.CompletionFn = fn
+	return opt
+}
+
+// SetValidateFn - Sets the function used to validate the option value after it is saved.
+func (opt *Option) SetValidateFn(fn func(interface{}) error) *Option {
+	opt.ValidateFn = fn
+	return opt
+}
+
+// Validate - Returns error if the option value doesn't pass the option validation function.
+func (opt *Option) Validate() error {
+	if opt.ValidateFn == nil {
+		return nil
+	}
+	err := opt.ValidateFn(opt.Value())
+	if err != nil {
+		return &ValidationError{Name: opt.Name, Alias: opt.UsedAlias, Err: err}
+	}
+	return nil
+}
+
 // SetEnvVar - Sets the name of the Env var that sets the option's value.
 func (opt *Option) SetEnvVar(name string) *Option {
 	opt.EnvVar = name
 	return opt
 }
 
+// SetConfigKey - Sets the key used to look up the option's value in a config file.
+func (opt *Option) SetConfigKey(key string) *Option {
+	opt.ConfigKey = key
+	return opt
+}
+
 // CheckRequired - Returns error if the option is required.
 func (opt *Option) CheckRequired() error {
 	if opt.IsRequired {
 		if !opt.Called {
-			if opt.IsRequiredErr != "" {
-				return fmt.Errorf(opt.IsRequiredErr)
-			}
-			return fmt.Errorf(text.ErrorMissingRequiredOption, opt.Name)
+			return &MissingRequiredError{Name: opt.Name, Msg: opt.IsRequiredErr}
 		}
 	}
 	return nil
@@ -246,6 +449,30 @@ func (opt *Option) SetFloat64(f float64) *Option {
 	return opt
 }
 
+// SetInt64 - Set the option's data.
+func (opt *Option) SetInt64(i int64) *Option {
+	*opt.pInt64 = i
+	return opt
+}
+
+// SetUint - Set the option's data.
+func (opt *Option) SetUint(i uint) *Option {
+	*opt.pUint = i
+	return opt
+}
+
+// SetUint64 - Set the option's data.
+func (opt *Option) SetUint64(i uint64) *Option {
+	*opt.pUint64 = i
+	return opt
+}
+
+// SetDuration - Set the option's data.
+func (opt *Option) SetDuration(d time.Duration) *Option {
+	*opt.pDuration = d
+	return opt
+}
+
 // SetStringSlice - Set the option's data.
 func (opt *Option) SetStringSlice(s []string) *Option {
 	*opt.pStringS = s
@@ -258,6 +485,30 @@ func (opt *Option) SetIntSlice(s []int) *Option {
 	return opt
 }
 
+// SetInt64Slice - Set the option's data.
+func (opt *Option) SetInt64Slice(s []int64) *Option {
+	*opt.pInt64S = s
+	return opt
+}
+
+// SetUintSlice - Set the option's data.
+func (opt *Option) SetUintSlice(s []uint) *Option {
+	*opt.pUintS = s
+	return opt
+}
+
+// SetUint64Slice - Set the option's data.
+func (opt *Option) SetUint64Slice(s []uint64) *Option {
+	*opt.pUint64S = s
+	return opt
+}
+
+// SetDurationSlice - Set the option's data.
+func (opt *Option) SetDurationSlice(s []time.Duration) *Option {
+	*opt.pDurationS = s
+	return opt
+}
+
 // SetKeyValueToStringMap - Set the option's data.
 func (opt *Option) SetKeyValueToStringMap(k, v string) *Option {
 	if opt.MapKeysToLower {
@@ -268,12 +519,24 @@ func (opt *Option) SetKeyValueToStringMap(k, v string) *Option {
 	return opt
 }
 
-// Save - Saves the data provided into the option
+// Save - Saves the data provided into the option.
+// After the data is saved, the value is validated with the option validation function.
 func (opt *Option) Save(a ...string) error {
 	if len(a) < 1 {
 		return nil
 	}
+	err := opt.save(a...)
+	if err != nil {
+		return err
+	}
+	return opt.Validate()
+}
+
+func (opt *Option) save(a ...string) error {
 	Debug.Printf("name: %s, optType: %d\n", opt.Name, opt.OptType)
+	if err := opt.checkValidValues(a...); err != nil {
+		return err
+	}
 	switch opt.OptType {
 	case StringType:
 		opt.SetString(a[0])
@@ -281,7 +544,7 @@ func (opt *Option) Save(a ...string) error {
 	case IntType:
 		i, err := strconv.Atoi(a[0])
 		if err != nil {
-			return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, a[0])
+			return opt.conversionError("int", a[0], err)
 		}
 		opt.SetInt(i)
 		return nil
@@ -289,7 +552,7 @@ func (opt *Option) Save(a ...string) error {
 		// TODO: Read the different errors when parsing float
 		i, err := strconv.ParseFloat(a[0], 64)
 		if err != nil {
-			return fmt.Errorf(text.ErrorConvertToFloat64, opt.UsedAlias, a[0])
+			return opt.conversionError("float64", a[0], err)
 		}
 		opt.SetFloat64(i)
 		return nil
@@ -307,12 +570,12 @@ func (opt *Option) Save(a ...string) error {
 				in1, err := strconv.Atoi(n1)
 				if err != nil {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError("int", e, err)
 				}
 				in2, err := strconv.Atoi(n2)
 				if err != nil {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError("int", e, err)
 				}
 				if in1 < in2 {
 					for j := in1; j <= in2; j++ {
@@ -320,12 +583,12 @@ func (opt *Option) Save(a ...string) error {
 					}
 				} else {
 					// TODO: Create new error description for this error.
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError("int", e, err)
 				}
 			} else {
 				i, err := strconv.Atoi(e)
 				if err != nil {
-					return fmt.Errorf(text.ErrorConvertToInt, opt.UsedAlias, e)
+					return opt.conversionError("int", e, err)
 				}
 				is = append(is, i)
 			}
@@ -339,6 +602,84 @@ func (opt *Option) Save(a ...string) error {
 		}
 		opt.SetKeyValueToStringMap(keyValue[0], keyValue[1])
 		return nil
+	case Int64Type:
+		i, err := strconv.ParseInt(a[0], 10, 64)
+		if err != nil {
+			return opt.conversionError("int64", a[0], err)
+		}
+		opt.SetInt64(i)
+		return nil
+	case UintType:
+		i, err := strconv.ParseUint(a[0], 10, 0)
+		if err != nil {
+			return opt.conversionError("uint", a[0], err)
+		}
+		opt.SetUint(uint(i))
+		return nil
+	case Uint64Type:
+		i, err := strconv.ParseUint(a[0], 10, 64)
+		if err != nil {
+			return opt.conversionError("uint64", a[0], err)
+		}
+		opt.SetUint64(i)
+		return nil
+	case DurationType:
+		d, err := time.ParseDuration(a[0])
+		if err != nil {
+			return opt.conversionError("duration", a[0], err)
+		}
+		opt.SetDuration(d)
+		return nil
+	case Int64RepeatType:
+		var is []int64
+		for _, e := range a {
+			i, err := strconv.ParseInt(e, 10, 64)
+			if err != nil {
+				return opt.conversionError("int64", e, err)
+			}
+			is = append(is, i)
+		}
+		opt.SetInt64Slice(append(*opt.pInt64S, is...))
+		return nil
+	case UintRepeatType:
+		var is []uint
+		for _, e := range a {
+			i, err := strconv.ParseUint(e, 10, 0)
+			if err != nil {
+				return opt.conversionError("uint", e, err)
+			}
+			is = append(is, uint(i))
+		}
+		opt.SetUintSlice(append(*opt.pUintS, is...))
+		return nil
+	case Uint64RepeatType:
+		var is []uint64
+		for _, e := range a {
+			i, err := strconv.ParseUint(e, 10, 64)
+			if err != nil {
+				return opt.conversionError("uint64", e, err)
+			}
+			is = append(is, i)
+		}
+		opt.SetUint64Slice(append(*opt.pUint64S, is...))
+		return nil
+	case DurationRepeatType:
+		var ds []time.Duration
+		for _, e := range a {
+			d, err := time.ParseDuration(e)
+			if err != nil {
+				return opt.conversionError("duration", e, err)
+			}
+			ds = append(ds, d)
+		}
+		opt.SetDurationSlice(append(*opt.pDurationS, ds...))
+		return nil
+	case ValueType:
+		err := opt.pValue.Set(a[0])
+		if err != nil {
+			return opt.conversionError(opt.pValue.Type(), a[0], err)
+		}
+		return nil
 	default: // BoolType:
 		if len(a) > 0 && a[0] == "true" {
 			opt.SetBool(true)
@@ -351,6 +692,26 @@ func (opt *Option) Save(a ...string) error {
 	}
 }
 
+// checkValidValues - Returns error if any of the arguments is not part of the ValidValues list.
+func (opt *Option) checkValidValues(a ...string) error {
+	if len(opt.ValidValues) == 0 || opt.OptType == BoolType {
+		return nil
+	}
+	for _, e := range a {
+		valid := false
+		for _, v := range opt.ValidValues {
+			if e == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return &InvalidValueError{Name: opt.Name, Alias: opt.UsedAlias, Argument: e, ValidValues: opt.ValidValues}
+		}
+	}
+	return nil
+}
+
 // Sort Interface
 func Sort(list []*Option) {
 	sort.Slice(list, func(i, j int) bool {
@@ -12,10 +12,34 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/zhizh/go-getoptions/text"
 )
 
+type testValue []string
+
+func (v *testValue) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("empty value")
+	}
+	*v = append(*v, s)
+	return nil
+}
+
+func (v *testValue) String() string { return fmt.Sprintf("%v", *v) }
+
+func (v *testValue) Type() string { return "list" }
+
+// testIntValue - User defined Value with a built in type name.
+type testIntValue int
+
+func (v *testIntValue) Set(s string) error { return fmt.Errorf("not a count") }
+
+func (v *testIntValue) String() string { return fmt.Sprintf("%d", *v) }
+
+func (v *testIntValue) Type() string { return "int" }
+
 func TestOption(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -172,6 +196,121 @@ func TestOption(t *testing.T) {
 			return New("help", StringMapType, &m)
 		}(), []string{"hola"}, map[string]string{},
 			fmt.Errorf(text.ErrorArgumentIsNotKeyValue, "")},
+
+		{"int64", func() *Option {
+			i := int64(0)
+			return New("help", Int64Type, &i)
+		}(), []string{"-123"}, int64(-123), nil},
+		{"int64 error", func() *Option {
+			i := int64(0)
+			return New("help", Int64Type, &i)
+		}(), []string{"123x"}, int64(0),
+			fmt.Errorf(text.ErrorConvertToInt64, "", "123x")},
+		{"uint", func() *Option {
+			i := uint(0)
+			return New("help", UintType, &i)
+		}(), []string{"123"}, uint(123), nil},
+		{"uint error", func() *Option {
+			i := uint(0)
+			return New("help", UintType, &i)
+		}(), []string{"-123"}, uint(0),
+			fmt.Errorf(text.ErrorConvertToUint, "", "-123")},
+		{"uint64", func() *Option {
+			i := uint64(0)
+			return New("help", Uint64Type, &i)
+		}(), []string{"123"}, uint64(123), nil},
+		{"uint64 error", func() *Option {
+			i := uint64(0)
+			return New("help", Uint64Type, &i)
+		}(), []string{"x"}, uint64(0),
+			fmt.Errorf(text.ErrorConvertToUint64, "", "x")},
+		{"duration", func() *Option {
+			d := time.Duration(0)
+			return New("help", DurationType, &d)
+		}(), []string{"1h"}, time.Hour, nil},
+		{"duration error", func() *Option {
+			d := time.Duration(0)
+			return New("help", DurationType, &d)
+		}(), []string{"1"}, time.Duration(0),
+			fmt.Errorf(text.ErrorConvertToDuration, "", "1")},
+		{"int64 slice", func() *Option {
+			ii := []int64{}
+			return New("help", Int64RepeatType, &ii)
+		}(), []string{"1", "2"}, []int64{1, 2}, nil},
+		{"int64 slice error", func() *Option {
+			ii := []int64{}
+			return New("help", Int64RepeatType, &ii)
+		}(), []string{"x"}, []int64{},
+			fmt.Errorf(text.ErrorConvertToInt64, "", "x")},
+		{"uint slice", func() *Option {
+			ii := []uint{}
+			return New("help", UintRepeatType, &ii)
+		}(), []string{"1", "2"}, []uint{1, 2}, nil},
+		{"uint slice error", func() *Option {
+			ii := []uint{}
+			return New("help", UintRepeatType, &ii)
+		}(), []string{"x"}, []uint{},
+			fmt.Errorf(text.ErrorConvertToUint, "", "x")},
+		{"uint64 slice", func() *Option {
+			ii := []uint64{}
+			return New("help", Uint64RepeatType, &ii)
+		}(), []string{"1", "2"}, []uint64{1, 2}, nil},
+		{"uint64 slice error", func() *Option {
+			ii := []uint64{}
+			return New("help", Uint64RepeatType, &ii)
+		}(), []string{"x"}, []uint64{},
+			fmt.Errorf(text.ErrorConvertToUint64, "", "x")},
+		{"duration slice", func() *Option {
+			dd := []time.Duration{}
+			return New("help", DurationRepeatType, &dd)
+		}(), []string{"1s", "1m"}, []time.Duration{time.Second, time.Minute}, nil},
+		{"duration slice error", func() *Option {
+			dd := []time.Duration{}
+			return New("help", DurationRepeatType, &dd)
+		}(), []string{"x"}, []time.Duration{},
+			fmt.Errorf(text.ErrorConvertToDuration, "", "x")},
+
+		{"valid values", func() *Option {
+			s := ""
+			return New("help", StringType, &s).SetValidValues("a", "b")
+		}(), []string{"b"}, "b", nil},
+		{"valid values error", func() *Option {
+			s := ""
+			return New("help", StringType, &s).SetValidValues("a", "b").SetCalled("h")
+		}(), []string{"c"}, "",
+			fmt.Errorf(text.ErrorInvalidValue, "h", "c", []string{"a", "b"})},
+		{"valid values slice error", func() *Option {
+			ss := []string{}
+			return New("help", StringRepeatType, &ss).SetValidValues("a", "b")
+		}(), []string{"a", "c"}, []string{},
+			fmt.Errorf(text.ErrorInvalidValue, "", "c", []string{"a", "b"})},
+
+		{"validate", func() *Option {
+			i := 0
+			return New("help", IntType, &i).SetValidateFn(func(v interface{}) error { return nil })
+		}(), []string{"1"}, 1, nil},
+		{"validate error", func() *Option {
+			i := 0
+			return New("help", IntType, &i).SetCalled("h").SetValidateFn(func(v interface{}) error {
+				if v.(int) > 0 {
+					return fmt.Errorf("must be negative")
+				}
+				return nil
+			})
+		}(), []string{"1"}, 1,
+			fmt.Errorf(text.ErrorValidation, "h", "must be negative")},
+
+		{"value", func() *Option {
+			return New("help", ValueType, &testValue{})
+		}(), []string{"hola"}, &testValue{"hola"}, nil},
+		{"value error", func() *Option {
+			return New("help", ValueType, &testValue{}).SetCalled("v")
+		}(), []string{""}, &testValue{},
+			fmt.Errorf(text.ErrorConvertToValue, "v", "list", "", "empty value")},
+		{"value error with built in type name", func() *Option {
+			return New("help", ValueType, new(testIntValue)).SetCalled("v")
+		}(), []string{"x"}, new(testIntValue),
+			fmt.Errorf(text.ErrorConvertToValue, "v", "int", "x", "not a count")},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -288,4 +427,19 @@ func TestOther(t *testing.T) {
 	if opt.HelpSynopsis != "--help <int>..." {
 		t.Errorf("got = '%#v', want '%#v'", opt.HelpSynopsis, "--help <int>...")
 	}
+
+	for typ, name := range map[Type]string{BoolType: "bool", IntRepeatType: "[]int", StringMapType: "map[string]string", DurationType: "duration", Type(99): "Type(99)"} {
+		if typ.String() != name {
+			t.Errorf("got = '%#v', want '%#v'", typ.String(), name)
+		}
+	}
+}
+
+func TestDashed(t *testing.T) {
+	if got := Dashed("a"); got != "-a" {
+		t.Errorf("Unexpected result: %s", got)
+	}
+	if got := Dashed("alias"); got != "--alias" {
+		t.Errorf("Unexpected result: %s", got)
+	}
 }
@@ -0,0 +1,97 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/zhizh/go-getoptions/text"
+)
+
+// ErrorCompletionDone - Indicates the completion results have been printed.
+// Only returned when SetNoExit is set.
+var ErrorCompletionDone = fmt.Errorf("completion done")
+
+// ErrorNoCommand - Indicates Dispatch was called without a command and the help has been printed.
+// Only returned when SetNoExit is set.
+var ErrorNoCommand = fmt.Errorf("no command")
+
+// SetNoExit - Return sentinel errors instead of calling os.Exit.
+// By default, Dispatch exits after printing the help and Parse exits after printing the completion results.
+// With SetNoExit they return ErrorHelpCalled, ErrorNoCommand or ErrorCompletionDone instead,
+// allowing deferred functions to run and making the program easier to test.
+//
+// The setting applies to the commands defined with NewCommand.
+// See Run for a helper that maps the errors to exit codes.
+func (gopt *GetOpt) SetNoExit() *GetOpt {
+	gopt.noExit = true
+	return gopt
+}
+
+// exit - Calls os.Exit with the given code, or returns err when SetNoExit is set on the GetOpt or any of its parents.
+func (gopt *GetOpt) exit(code int, err error) error {
+	for g := gopt; g != nil; g = g.parent {
+		if g.noExit {
+			return err
+		}
+	}
+	exitFn(code)
+	return nil
+}
+
+// Run - Parses the arguments and dispatches to the command functions, returning an exit code.
+// While it runs, it sets SetNoExit so it never calls os.Exit itself, and SetRequireOrder when there are commands
+// so the options after the command name are parsed by the command.
+// Both settings are restored before it returns. Use it like:
+//
+//     os.Exit(opt.Run(ctx, os.Args[1:]))
+//
+// When there are no commands, the GetOpt CommandFn is called with the remaining arguments.
+// The "help" command and option are handled as in Dispatch,
+// the help option prints the help even when other options are missing or invalid.
+//
+// Exit codes:
+//
+//     0   - Success.
+//     1   - Error, the error is printed to opt.Writer. Also returned when the help is printed.
+//     124 - Completion results printed.
+func (gopt *GetOpt) Run(ctx context.Context, args []string) int {
+	noExit, requireOrder := gopt.noExit, gopt.requireOrder
+	defer func() { gopt.noExit, gopt.requireOrder = noExit, requireOrder }()
+	gopt.SetNoExit()
+	if len(gopt.commands) > 0 {
+		// Leave the command options to the command
+		gopt.SetRequireOrder()
+	}
+	remaining, err := gopt.Parse(args)
+	switch {
+	case errors.Is(err, ErrorCompletionDone):
+	// The help is printed even if other options are missing or invalid.
+	case gopt.Called("help") && (err != nil || len(gopt.commands) == 0):
+		fmt.Fprint(gopt.Writer, gopt.Help())
+		err = ErrorHelpCalled
+	case err != nil:
+	case len(gopt.commands) > 0:
+		err = gopt.Dispatch(ctx, "help", remaining)
+	case gopt.CommandFn != nil:
+		err = gopt.CommandFn(ctx, gopt, remaining)
+	}
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrorCompletionDone):
+		return 124
+	case errors.Is(err, ErrorHelpCalled), errors.Is(err, ErrorNoCommand):
+		return 1
+	}
+	fmt.Fprintf(gopt.Writer, text.MessageOnError, err)
+	return 1
+}
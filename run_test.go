@@ -0,0 +1,208 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zhizh/go-getoptions/text"
+)
+
+func TestNoExit(t *testing.T) {
+	called := false
+	defer func(fn func(int)) { exitFn = fn }(exitFn)
+	exitFn = func(code int) { called = true }
+	defer os.Setenv("COMP_LINE", "")
+	defer func() { completionWriter = os.Stdout }()
+
+	setup := func() (*GetOpt, *bytes.Buffer) {
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		opt.SetNoExit()
+		opt.SetRequireOrder()
+		opt.SetUnknownMode(Pass)
+		log := opt.NewCommand("log", "")
+		log.NewCommand("tail", "")
+		return opt, buf
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected error
+	}{
+		{"no command", []string{}, ErrorNoCommand},
+		{"help", []string{"help"}, ErrorHelpCalled},
+		{"help command", []string{"help", "log"}, ErrorHelpCalled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			opt, buf := setup()
+			err := opt.Dispatch(context.Background(), "help", tt.args)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if called {
+				t.Errorf("exit was called")
+			}
+			if buf.String() == "" {
+				t.Errorf("Help wasn't printed")
+			}
+		})
+	}
+
+	t.Run("inherited by commands", func(t *testing.T) {
+		called = false
+		opt, buf := setup()
+		opt.commands["log"].Writer = buf
+		err := opt.commands["log"].Dispatch(context.Background(), "help", []string{})
+		if !errors.Is(err, ErrorNoCommand) || called {
+			t.Errorf("Unexpected error: %v, exit called: %v", err, called)
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		called = false
+		buf := new(bytes.Buffer)
+		completionWriter = buf
+		os.Setenv("COMP_LINE", "test lo")
+		defer os.Setenv("COMP_LINE", "")
+		opt, _ := setup()
+		_, err := opt.Parse([]string{})
+		if !errors.Is(err, ErrorCompletionDone) || called {
+			t.Errorf("Unexpected error: %v, exit called: %v", err, called)
+		}
+		if buf.String() != "log\n" {
+			t.Errorf("Unexpected completions: %s", buf.String())
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	called := false
+	defer func(fn func(int)) { exitFn = fn }(exitFn)
+	exitFn = func(code int) { called = true }
+	defer func() { completionWriter = os.Stdout }()
+
+	setup := func() (*GetOpt, *bytes.Buffer) {
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		opt.SetRequireOrder()
+		opt.SetUnknownMode(Pass)
+		opt.Bool("help", false)
+		opt.NewCommand("ok", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		opt.NewCommand("fail", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return fmt.Errorf("failed") })
+		opt.HelpCommand("")
+		return opt, buf
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		expected string
+	}{
+		{"success", []string{"ok"}, 0, ""},
+		{"error", []string{"fail"}, 1, fmt.Sprintf(text.MessageOnError, "failed")},
+		{"unknown command", []string{"x"}, 1, fmt.Sprintf(text.MessageOnError, fmt.Sprintf(text.ErrorNotACommand, "x"))},
+		{"help", []string{"help"}, 1, "SYNOPSIS"},
+		{"help option", []string{"ok", "--help"}, 1, "SYNOPSIS"},
+		{"no command", []string{}, 1, "SYNOPSIS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			opt, buf := setup()
+			code := opt.Run(context.Background(), tt.args)
+			if code != tt.code {
+				t.Errorf("Unexpected code: %d, expected %d", code, tt.code)
+			}
+			if called {
+				t.Errorf("exit was called")
+			}
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("Unexpected output: '%s', expected '%s'", buf.String(), tt.expected)
+			}
+		})
+	}
+
+	t.Run("completion", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		completionWriter = buf
+		os.Setenv("COMP_LINE", "test o")
+		defer os.Setenv("COMP_LINE", "")
+		opt, _ := setup()
+		if code := opt.Run(context.Background(), []string{}); code != 124 {
+			t.Errorf("Unexpected code: %d", code)
+		}
+		if buf.String() != "ok\n" {
+			t.Errorf("Unexpected completions: %s", buf.String())
+		}
+	})
+
+	t.Run("no commands", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		opt.Bool("help", false)
+		var got []string
+		opt.SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error {
+			got = args
+			return nil
+		})
+		if code := opt.Run(context.Background(), []string{"a", "b"}); code != 0 || strings.Join(got, " ") != "a b" {
+			t.Errorf("Unexpected code: %d, args: %v", code, got)
+		}
+		if code := opt.Run(context.Background(), []string{"--help"}); code != 1 || !strings.Contains(buf.String(), "SYNOPSIS") {
+			t.Errorf("Unexpected code: %d, output: %s", code, buf.String())
+		}
+	})
+
+	t.Run("command options", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		opt := New()
+		opt.Writer = buf
+		opt.Bool("help", false)
+		log := opt.NewCommand("log", "")
+		follow := log.Bool("follow", false)
+		log.SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+		if code := opt.Run(context.Background(), []string{"log", "--follow"}); code != 0 || !*follow {
+			t.Errorf("Unexpected code: %d, follow: %v, output: %s", code, *follow, buf.String())
+		}
+		// The settings are restored
+		if opt.requireOrder || opt.noExit {
+			t.Errorf("Unexpected settings: requireOrder %v, noExit %v", opt.requireOrder, opt.noExit)
+		}
+	})
+
+	t.Run("help with parse errors", func(t *testing.T) {
+		for _, withCommands := range []bool{false, true} {
+			buf := new(bytes.Buffer)
+			opt := New()
+			opt.Writer = buf
+			opt.Bool("help", false)
+			opt.String("host", "", opt.Required())
+			if withCommands {
+				opt.NewCommand("ok", "").SetCommandFn(func(ctx context.Context, opt *GetOpt, args []string) error { return nil })
+			}
+			if code := opt.Run(context.Background(), []string{"--help"}); code != 1 || !strings.Contains(buf.String(), "SYNOPSIS") || strings.Contains(buf.String(), "ERROR") {
+				t.Errorf("Unexpected code: %d, output: %s", code, buf.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,163 @@
+// This file is part of go-getoptions.
+//
+// Copyright (C) 2015-2021  David Gamba Rios
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package getoptions
+
+import (
+	"sort"
+
+	"github.com/zhizh/go-getoptions/completion"
+	"github.com/zhizh/go-getoptions/option"
+)
+
+// Schema - Machine readable description of a program or command.
+// It can be serialised with encoding/json.
+//
+// Options lists the options defined on the program or command itself,
+// commands also accept the options defined on their parents.
+type Schema struct {
+	Name          string            `json:"name"`
+	Description   string            `json:"description,omitempty"`
+	Hidden        bool              `json:"hidden,omitempty"`
+	Deprecated    bool              `json:"deprecated,omitempty"`
+	DeprecatedMsg string            `json:"deprecated_msg,omitempty"`
+	Options       []SchemaOption    `json:"options,omitempty"`
+	Arguments     []SchemaOption    `json:"arguments,omitempty"` // Positional arguments in definition order
+	Commands      []*Schema         `json:"commands,omitempty"`
+	Completion    *SchemaCompletion `json:"completion,omitempty"` // Completion of the command arguments
+}
+
+// SchemaOption - Machine readable description of an option or positional argument.
+type SchemaOption struct {
+	Name          string            `json:"name"`
+	Aliases       []string          `json:"aliases"`
+	Type          string            `json:"type"` // Go type name of the option value, see option.Type, or value:<Type()> for user defined values
+	Description   string            `json:"description,omitempty"`
+	Default       string            `json:"default,omitempty"`
+	EnvVar        string            `json:"env_var,omitempty"`
+	ConfigKey     string            `json:"config_key,omitempty"`
+	IsRequired    bool              `json:"required"`
+	IsOptional    bool              `json:"optional"` // Indicates if the option argument is optional
+	MinArgs       int               `json:"min_args"`
+	MaxArgs       int               `json:"max_args"`
+	ValidValues   []string          `json:"valid_values,omitempty"`
+	Completion    *SchemaCompletion `json:"completion,omitempty"`
+	Hidden        bool              `json:"hidden,omitempty"`
+	Deprecated    bool              `json:"deprecated,omitempty"`
+	DeprecatedMsg string            `json:"deprecated_msg,omitempty"`
+}
+
+// SchemaCompletion - Machine readable description of how an argument is completed.
+type SchemaCompletion struct {
+	Values   []string `json:"values,omitempty"`   // Static list of completions
+	Files    bool     `json:"files,omitempty"`    // Completed with file names
+	Patterns []string `json:"patterns,omitempty"` // Glob patterns that restrict the completed file names
+	Dirs     bool     `json:"dirs,omitempty"`     // Completed with directory names
+	Dynamic  bool     `json:"dynamic,omitempty"`  // Completed by a function at completion time
+}
+
+// Schema - Returns a machine readable description of the program or command and all its commands.
+// For example, to export it as JSON:
+//
+//     out, err := json.MarshalIndent(opt.Schema(), "", "  ")
+//
+// Options, commands and completion values are sorted by name for a stable output.
+// Hidden options and commands are included and flagged.
+func (gopt *GetOpt) Schema() *Schema {
+	schema := &Schema{
+		Name:          gopt.name,
+		Description:   gopt.description,
+		Hidden:        gopt.hidden,
+		Deprecated:    gopt.deprecated,
+		DeprecatedMsg: gopt.deprecatedMsg,
+	}
+
+	for _, opt := range gopt.obj {
+		if gopt.inherited(opt) {
+			continue
+		}
+		schema.Options = append(schema.Options, schemaOption(opt))
+	}
+	sort.Slice(schema.Options, func(i, j int) bool { return schema.Options[i].Name < schema.Options[j].Name })
+
+	for _, arg := range gopt.arguments {
+		schema.Arguments = append(schema.Arguments, schemaOption(arg))
+	}
+
+	c := &SchemaCompletion{}
+	for _, node := range gopt.completion.GetChildrenByKind(completion.CustomNode) {
+		c.Values = append(c.Values, node.GetEntries()...)
+	}
+	for _, node := range gopt.completion.GetChildrenByKind(completion.FileListNode) {
+		c.Files = true
+		c.Patterns = append(c.Patterns, node.Patterns...)
+	}
+	c.Dirs = len(gopt.completion.GetChildrenByKind(completion.DirListNode)) > 0
+	c.Dynamic = len(gopt.completion.GetChildrenByKind(completion.CallbackNode)) > 0
+	if len(c.Values) > 0 || c.Files || c.Dirs || c.Dynamic {
+		sort.Strings(c.Values)
+		schema.Completion = c
+	}
+
+	for _, command := range gopt.commands {
+		schema.Commands = append(schema.Commands, command.Schema())
+	}
+	sort.Slice(schema.Commands, func(i, j int) bool { return schema.Commands[i].Name < schema.Commands[j].Name })
+	return schema
+}
+
+// inherited - Indicates if the option was passed to the command by one of its parents.
+func (gopt *GetOpt) inherited(opt *option.Option) bool {
+	for p := gopt.parent; p != nil; p = p.parent {
+		if p.obj[opt.Name] == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaType - Returns the option type name, user defined values include their Type(): value:<Type()>.
+func schemaType(opt *option.Option) string {
+	if v, ok := opt.Value().(option.Value); ok && opt.OptType == option.ValueType {
+		return opt.OptType.String() + ":" + v.Type()
+	}
+	return opt.OptType.String()
+}
+
+// schemaOption - Returns the machine readable description of the option.
+func schemaOption(opt *option.Option) SchemaOption {
+	s := SchemaOption{
+		Name:          opt.Name,
+		Aliases:       append([]string{}, opt.Aliases...),
+		Type:          schemaType(opt),
+		Description:   opt.Description,
+		Default:       opt.DefaultStr,
+		EnvVar:        opt.EnvVar,
+		ConfigKey:     opt.ConfigKey,
+		IsRequired:    opt.IsRequired,
+		IsOptional:    opt.IsOptional,
+		MinArgs:       opt.MinArgs,
+		MaxArgs:       opt.MaxArgs,
+		ValidValues:   opt.ValidValues,
+		Hidden:        opt.IsHidden,
+		Deprecated:    opt.IsDeprecated,
+		DeprecatedMsg: opt.DeprecatedMsg,
+	}
+	// Same precedence as the value completion
+	switch {
+	case opt.CompletionFn != nil:
+		s.Completion = &SchemaCompletion{Dynamic: true}
+	case len(opt.ValidValues) > 0:
+		s.Completion = &SchemaCompletion{Values: opt.ValidValues}
+	case opt.DirCompletion:
+		s.Completion = &SchemaCompletion{Dirs: true}
+	case opt.FileCompletion:
+		s.Completion = &SchemaCompletion{Files: true, Patterns: opt.FilePatterns}
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+package getoptions
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	setup := func() *GetOpt {
+		opt := New()
+		opt.name = "tool"
+		opt.Self("", "A tool.")
+		opt.Bool("debug", false, opt.Alias("d"))
+		opt.String("profile", "default", opt.GetEnv("TOOL_PROFILE"), opt.Description("Profile to use."), opt.ValidValues("default", "dev"))
+		opt.StringSlice("tag", 1, 3)
+		opt.IntOptional("level", 1)
+		log := opt.NewCommand("log", "Show logs")
+		log.String("config", "", opt.Required(), opt.CompleteFiles("*.yaml"))
+		log.String("branch", "", opt.CompletionFn(func(ctx context.Context, prefix string) []string { return nil }))
+		log.StringArg("dir", opt.CompleteDirs())
+		log.CustomCompletion([]string{"b", "a"})
+		return opt
+	}
+
+	expected := `{
+  "name": "tool",
+  "description": "A tool.",
+  "options": [
+    {
+      "name": "debug",
+      "aliases": [
+        "debug",
+        "d"
+      ],
+      "type": "bool",
+      "default": "false",
+      "required": false,
+      "optional": false,
+      "min_args": 0,
+      "max_args": 0
+    },
+    {
+      "name": "level",
+      "aliases": [
+        "level"
+      ],
+      "type": "int",
+      "default": "1",
+      "required": false,
+      "optional": true,
+      "min_args": 0,
+      "max_args": 0
+    },
+    {
+      "name": "profile",
+      "aliases": [
+        "profile"
+      ],
+      "type": "string",
+      "description": "Profile to use.",
+      "default": "\"default\"",
+      "env_var": "TOOL_PROFILE",
+      "required": false,
+      "optional": false,
+      "min_args": 0,
+      "max_args": 0,
+      "valid_values": [
+        "default",
+        "dev"
+      ],
+      "completion": {
+        "values": [
+          "default",
+          "dev"
+        ]
+      }
+    },
+    {
+      "name": "tag",
+      "aliases": [
+        "tag"
+      ],
+      "type": "[]string",
+      "default": "[]",
+      "required": false,
+      "optional": false,
+      "min_args": 1,
+      "max_args": 3
+    }
+  ],
+  "commands": [
+    {
+      "name": "log",
+      "description": "Show logs",
+      "options": [
+        {
+          "name": "branch",
+          "aliases": [
+            "branch"
+          ],
+          "type": "string",
+          "default": "\"\"",
+          "required": false,
+          "optional": false,
+          "min_args": 0,
+          "max_args": 0,
+          "completion": {
+            "dynamic": true
+          }
+        },
+        {
+          "name": "config",
+          "aliases": [
+            "config"
+          ],
+          "type": "string",
+          "default": "\"\"",
+          "required": true,
+          "optional": false,
+          "min_args": 0,
+          "max_args": 0,
+          "completion": {
+            "files": true,
+            "patterns": [
+              "*.yaml"
+            ]
+          }
+        }
+      ],
+      "arguments": [
+        {
+          "name": "dir",
+          "aliases": [
+            "dir"
+          ],
+          "type": "string",
+          "required": false,
+          "optional": false,
+          "min_args": 0,
+          "max_args": 0,
+          "completion": {
+            "dirs": true
+          }
+        }
+      ],
+      "completion": {
+        "values": [
+          "a",
+          "b"
+        ],
+        "dirs": true
+      }
+    }
+  ]
+}`
+
+	t.Run("json", func(t *testing.T) {
+		out, err := json.MarshalIndent(setup().Schema(), "", "  ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if string(out) != expected {
+			t.Errorf("Unexpected schema:\n%s", firstDiff(string(out), expected))
+		}
+	})
+
+	t.Run("inherited options are not repeated after Parse", func(t *testing.T) {
+		opt := setup()
+		before := opt.Schema()
+		opt.SetRequireOrder()
+		opt.SetUnknownMode(Pass)
+		_, err := opt.Parse([]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		after := opt.Schema()
+		if !reflect.DeepEqual(before, after) {
+			t.Errorf("Unexpected schema after Parse:\n%#v\n%#v", before, after)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		schema := setup().Schema()
+		var got Schema
+		err := json.Unmarshal([]byte(expected), &got)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(&got, schema) {
+			t.Errorf("Unexpected schema:\n%#v\n%#v", &got, schema)
+		}
+	})
+}
@@ -21,6 +21,18 @@ var ErrorAmbiguousArgument = "Ambiguous option '%s', matches %v!"
 // It has a string placeholder '%s' for the name of the missing option.
 var ErrorMissingRequiredOption = "Missing required option '%s'!"
 
+// ErrorMutuallyExclusiveOptions holds the text for the error when more than one option of a mutually exclusive group is called.
+// It has two string placeholders ('%s') for the names of the options called together.
+var ErrorMutuallyExclusiveOptions = "Options '%s' and '%s' are mutually exclusive!"
+
+// ErrorRequiredTogetherOptions holds the text for the error when only some of the options that are required together are called.
+// It has two string placeholders ('%s'). The first one for the name of the called option and the second one for the name of the missing option.
+var ErrorRequiredTogetherOptions = "Option '%s' requires option '%s'!"
+
+// ErrorAtLeastOneOfOptions holds the text for the error when none of the options of an "at least one of" group are called.
+// It has a []string list placeholder for the names of the options in the group.
+var ErrorAtLeastOneOfOptions = "Missing required option, at least one of %v is required!"
+
 // ErrorArgumentIsNotKeyValue holds the text for Map type options where the argument is not of key=value type.
 // It has a string placeholder '%s' for the name of the option missing the argument.
 var ErrorArgumentIsNotKeyValue = "Argument error for option '%s': Should be of type 'key=value'!"
@@ -30,6 +42,10 @@ var ErrorArgumentIsNotKeyValue = "Argument error for option '%s': Should be of t
 var ErrorArgumentWithDash = "Missing argument for option '%s'!\n" +
 	"If passing arguments that start with '-' use --option=-argument"
 
+// ErrorConvertToBool holds the text for Bool Coversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToBool = "Argument error for option '%s': Can't convert string to bool: '%s'"
+
 // ErrorConvertToInt holds the text for Int Coversion argument error.
 // It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
 var ErrorConvertToInt = "Argument error for option '%s': Can't convert string to int: '%s'"
@@ -38,10 +54,82 @@ var ErrorConvertToInt = "Argument error for option '%s': Can't convert string to
 // It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
 var ErrorConvertToFloat64 = "Argument error for option '%s': Can't convert string to float64: '%s'"
 
+// ErrorConvertToInt64 holds the text for Int64 Coversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToInt64 = "Argument error for option '%s': Can't convert string to int64: '%s'"
+
+// ErrorConvertToUint holds the text for Uint Coversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToUint = "Argument error for option '%s': Can't convert string to uint: '%s'"
+
+// ErrorConvertToUint64 holds the text for Uint64 Coversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToUint64 = "Argument error for option '%s': Can't convert string to uint64: '%s'"
+
+// ErrorConvertToDuration holds the text for Duration Coversion argument error.
+// It has two string placeholders ('%s'). The first one for the name of the option with the wrong argument and the second one for the argument that could not be converted.
+var ErrorConvertToDuration = "Argument error for option '%s': Can't convert string to duration: '%s'"
+
+// ErrorConvertToValue holds the text for user defined type Coversion argument error.
+// It has four string placeholders ('%s'). The first one for the name of the option with the wrong argument, the second one for the name of the type, the third one for the argument that could not be converted and the fourth one for the error returned by the type.
+var ErrorConvertToValue = "Argument error for option '%s': Can't convert string to %s: '%s': %s"
+
+// ErrorInvalidValue holds the text for the error when the argument is not part of the list of valid values.
+// It has two string placeholders ('%s') and a []string list. The first one for the name of the option with the wrong argument, the second one for the argument and the list for the valid values.
+var ErrorInvalidValue = "Argument error for option '%s': Invalid value '%s', valid values: %v"
+
+// ErrorValidation holds the text for the error returned when the option value doesn't pass its validation function.
+// It has two string placeholders ('%s'). The first one for the name of the option and the second one for the error returned by the validation function.
+var ErrorValidation = "Argument error for option '%s': %s"
+
+// ErrorConfigFile holds the text for the error returned when the config file can't be read or parsed.
+// It has two placeholders ('%s'). The first one for the config file path and the second one for the underlying error.
+var ErrorConfigFile = "Error reading config file '%s': %s"
+
+// ErrorMissingRequiredArgument holds the text for missing required positional argument error.
+// It has a string placeholder '%s' for the name of the missing argument.
+var ErrorMissingRequiredArgument = "Missing required argument '%s'!"
+
+// ErrorTooManyArguments holds the text for the error returned when more positional arguments than the ones defined are passed.
+// It has a []string placeholder ('%v') for the unexpected arguments.
+var ErrorTooManyArguments = "Too many arguments, unexpected: %v"
+
 // MessageOnUnknown holds the text for the unknown option message.
 // It has a string placeholder '%s' for the name of the option missing the argument.
 var MessageOnUnknown = "Unknown option '%s'"
 
+// MessageDidYouMean holds the text appended to unknown option and unknown command errors when there are similar alternatives.
+// It has a string placeholder '%s' for the comma separated list of suggestions.
+var MessageDidYouMean = "Did you mean: %s?"
+
+// ErrorNotACommand holds the text for the error returned by Dispatch when the argument is not a command.
+// It has a string placeholder '%s' for the argument.
+var ErrorNotACommand = "not a command: '%s'"
+
+// ErrorNotACommandOrOption holds the text for the error returned by Dispatch when the argument looks like an option but it is not a command or a valid option.
+// It has a string placeholder '%s' for the argument.
+var ErrorNotACommandOrOption = "not a command or a valid option: '%s'\n" +
+	"       Did you mean to pass it after the command?"
+
+// ErrorUnknownHelpEntry holds the text for the error returned by Dispatch when the help command is called with an unknown command.
+// It has a string placeholder '%s' for the argument.
+var ErrorUnknownHelpEntry = "unknown help entry '%s'"
+
+// ErrorUnsupportedShell holds the text for the error returned when generating a completion script for an unsupported shell.
+// It has a string placeholder '%s' for the name of the shell.
+var ErrorUnsupportedShell = "unsupported shell '%s'"
+
+// HelpCompletionCommandDescription holds the description of the completion command, it documents how to install the completion scripts.
+// It has a placeholder '%[1]s' for the name of the program.
+var HelpCompletionCommandDescription = "Print the shell completion script.\n" +
+	"Bash, add to ~/.bashrc: source <(%[1]s completion bash)\n" +
+	"Zsh, run once: %[1]s completion zsh > \"${fpath[1]}/_%[1]s\"\n" +
+	"Fish, run once: %[1]s completion fish > ~/.config/fish/completions/%[1]s.fish"
+
+// MessageOnError holds the text for the error message printed by Run.
+// It has a string placeholder '%s' for the error.
+var MessageOnError = "ERROR: %s\n"
+
 // MessageOnInterrupt holds the text for the message to be printed when an interrupt is received.
 var MessageOnInterrupt = "Interrupt signal received"
 
@@ -59,3 +147,87 @@ var HelpRequiredOptionsHeader = "REQUIRED PARAMETERS"
 
 // HelpOptionsHeader holds the header text for the option list
 var HelpOptionsHeader = "OPTIONS"
+
+// HelpArgumentsHeader holds the header text for the positional argument list
+var HelpArgumentsHeader = "ARGUMENTS"
+
+// HelpEnvironmentHeader holds the header text for the environment variable list in man pages
+var HelpEnvironmentHeader = "ENVIRONMENT"
+
+// HelpSeeAlsoHeader holds the header text for the related pages list in man pages
+var HelpSeeAlsoHeader = "SEE ALSO"
+
+// HelpEnvVarDescription holds the description of an environment variable in man pages.
+// It has a string placeholder '%s' for the option name.
+var HelpEnvVarDescription = "Sets the value of %s."
+
+// HelpDescriptionHeader holds the header text for the long description in man pages
+var HelpDescriptionHeader = "DESCRIPTION"
+
+// HelpDocCommandColumn holds the header of the command column in reference documents
+var HelpDocCommandColumn = "Command"
+
+// HelpDocArgumentColumn holds the header of the argument column in reference documents
+var HelpDocArgumentColumn = "Argument"
+
+// HelpDocOptionColumn holds the header of the option column in reference documents
+var HelpDocOptionColumn = "Option"
+
+// HelpDocAliasesColumn holds the header of the aliases column in reference documents
+var HelpDocAliasesColumn = "Aliases"
+
+// HelpDocTypeColumn holds the header of the type column in reference documents
+var HelpDocTypeColumn = "Type"
+
+// HelpDocDefaultColumn holds the header of the default column in reference documents
+var HelpDocDefaultColumn = "Default"
+
+// HelpDocEnvColumn holds the header of the environment variable column in reference documents
+var HelpDocEnvColumn = "Env"
+
+// HelpDocRequiredColumn holds the header of the required column in reference documents
+var HelpDocRequiredColumn = "Required"
+
+// HelpDocDescriptionColumn holds the header of the description column in reference documents
+var HelpDocDescriptionColumn = "Description"
+
+// HelpDocRequiredValue holds the value of the required column for required options and arguments in reference documents
+var HelpDocRequiredValue = "yes"
+
+// CompatRemovedCommand holds the text reported by the compat package when a command is removed.
+var CompatRemovedCommand = "command removed"
+
+// CompatRemovedOption holds the text reported by the compat package when an option is removed.
+// It has a string placeholder '%s' for the option.
+var CompatRemovedOption = "option '%s' removed"
+
+// CompatRemovedAlias holds the text reported by the compat package when an option alias is removed.
+// It has string placeholders '%s' for the alias and the option.
+var CompatRemovedAlias = "alias '%s' of option '%s' removed"
+
+// CompatChangedType holds the text reported by the compat package when the type of an option changes.
+// It has string placeholders '%s' for the option, the old type and the new type.
+var CompatChangedType = "option '%s' type changed from '%s' to '%s'"
+
+// CompatBecameRequired holds the text reported by the compat package when an option becomes required.
+// It has a string placeholder '%s' for the option.
+var CompatBecameRequired = "option '%s' is now required"
+
+// CompatChangedEnvVar holds the text reported by the compat package when the environment variable of an option changes.
+// It has string placeholders '%s' for the option, the old environment variable and the new one, which can be empty.
+var CompatChangedEnvVar = "option '%s' env var changed from '%s' to '%s'"
+
+// MessageWarning holds the text for the warnings written to the GetOpt Writer.
+// It has a string placeholder '%s' for the warning.
+var MessageWarning = "WARNING: %s\n"
+
+// WarningDeprecatedOption holds the text for the warning printed when a deprecated option is used.
+// It has a string placeholder '%s' for the option as it was called (alias, env var or config file key), the deprecation message is appended to it.
+var WarningDeprecatedOption = "option '%s' is deprecated"
+
+// WarningDeprecatedCommand holds the text for the warning printed when a deprecated command is used.
+// It has a string placeholder '%s' for the command, the deprecation message is appended to it.
+var WarningDeprecatedCommand = "command '%s' is deprecated"
+
+// HelpDeprecated holds the text that flags deprecated options and commands in the help, the deprecation message is appended to it.
+var HelpDeprecated = "deprecated"